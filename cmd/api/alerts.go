@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/gorilla/mux"
+)
+
+//RuleRequest - POST data of alert rule
+type RuleRequest struct {
+	Kind      string         `json:"kind"`
+	Threshold uint64         `json:"threshold"`
+	Window    alert.Duration `json:"window"`
+	Before    alert.Duration `json:"before"`
+	Notifier  string         `json:"notifier"`
+	Target    string         `json:"target"`
+}
+
+//durationRule - duration from min to max
+func durationRule(min, max time.Duration) validation.Rule {
+	return validation.By(func(value interface{}) error {
+		d := time.Duration(value.(alert.Duration))
+
+		if d < min || d > max {
+			return fmt.Errorf("must be from %s to %s", min, max)
+		}
+		return nil
+	})
+}
+
+//Validate ...
+func (rr *RuleRequest) Validate(notifiers map[string]alert.Notifier) error {
+	names := []interface{}{}
+	for name := range notifiers {
+		names = append(names, name)
+	}
+
+	target := is.URL.Error("invalid url")
+	if rr.Notifier == alert.NotifierEmail {
+		target = is.Email.Error("invalid email")
+	}
+
+	fields := []*validation.FieldRules{
+		validation.Field(&rr.Kind, validation.Required.Error("is required"), validation.In(alert.Kinds...).Error("unknown kind")),
+		validation.Field(&rr.Notifier, validation.Required.Error("is required"), validation.In(names...).Error("unknown or disabled notifier")),
+		validation.Field(&rr.Target, validation.Required.Error("is required"), target),
+	}
+
+	switch rr.Kind {
+	case alert.KindVisits:
+		fields = append(fields,
+			validation.Field(&rr.Threshold, validation.Required.Error("is required")),
+			validation.Field(&rr.Window, durationRule(time.Minute, alert.MaxWindow)),
+		)
+	case alert.KindExpiring:
+		fields = append(fields, validation.Field(&rr.Before, durationRule(time.Minute, alert.MaxBefore)))
+	}
+
+	return validation.ValidateStruct(rr, fields...)
+}
+
+//manageItem - load item and check management token, writes error response if it is not possible
+func (s *Server) manageItem(w http.ResponseWriter, r *http.Request) (*store.Item, bool) {
+	item, ok := s.itemFromRequest(w, r)
+
+	if !ok {
+		return nil, false
+	}
+
+	if !s.canManage(r, item) {
+		s.ResponseJSON(w, &Response{"error", "forbidden"}, http.StatusForbidden)
+		return nil, false
+	}
+
+	return item, true
+}
+
+//CreateAlert - add alert rule to link
+func (s *Server) CreateAlert(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.manageItem(w, r)
+
+	if !ok {
+		return
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	rr := RuleRequest{}
+
+	if err := dec.Decode(&rr); err != nil {
+		s.ResponseJSON(w, &Response{"error", "bad json"}, 400)
+		return
+	}
+
+	if err := rr.Validate(s.notifiers); err != nil {
+		s.ResponseJSON(w, &Response{"error", err.Error()}, 400)
+		return
+	}
+
+	// anyone can create link, so email to any address would make the server a mail relay
+	if rr.Notifier == alert.NotifierEmail && !s.isAdmin(r) {
+		s.ResponseJSON(w, &Response{"error", "notifier: email alerts can be created only with admin token."}, http.StatusForbidden)
+		return
+	}
+
+	// the server calls webhook itself, so it can't point to internal services
+	if rr.Notifier == alert.NotifierWebhook && s.targets != nil {
+		if violations := s.targets.Check(rr.Target); len(violations) > 0 {
+			s.ResponseJSON(w, &RejectResponse{"error", "target: rejected by policy.", violations}, 400)
+			return
+		}
+	}
+
+	rules, err := s.alerts.List(item.ID)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	if len(rules) >= alert.MaxRules {
+		s.ResponseJSON(w, &Response{"error", fmt.Sprintf("link can't have more than %d rules", alert.MaxRules)}, 400)
+		return
+	}
+
+	rule := &alert.Rule{
+		ID: events.NewID(), LinkID: item.ID, Kind: rr.Kind, Notifier: rr.Notifier, Target: rr.Target,
+		Created: time.Now().UTC(),
+	}
+
+	switch rr.Kind {
+	case alert.KindVisits:
+		rule.Threshold, rule.Window = rr.Threshold, rr.Window
+	case alert.KindExpiring:
+		rule.Before = rr.Before
+	}
+
+	if err = s.alerts.Add(rule); err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, rule, 200)
+}
+
+//ListAlerts - alert rules of link
+func (s *Server) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.manageItem(w, r)
+
+	if !ok {
+		return
+	}
+
+	rules, err := s.alerts.List(item.ID)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, rules, 200)
+}
+
+//DeleteAlert - remove alert rule of link
+func (s *Server) DeleteAlert(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.manageItem(w, r)
+
+	if !ok {
+		return
+	}
+
+	err := s.alerts.Remove(item.ID, mux.Vars(r)["rule"])
+
+	if err != nil {
+		if err == alert.ErrNotFound {
+			s.response404(w, r)
+			return
+		}
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, &Response{"success", "rule is deleted"}, 200)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+)
+
+func TestAlertHandlers(t *testing.T) {
+	s := GetTestAPI()
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://vk.com/sale", "expire": "10.1.2380 1:0:0"}`, encoded)
+	code := encoded.URL[strings.LastIndex(encoded.URL, "/")+1:]
+	path := "/" + code + "/alerts"
+
+	tests := map[string]struct {
+		token string
+		data  string
+		code  int
+	}{
+		"Without token":        {"", `{"kind": "consumed", "notifier": "webhook", "target": "https://crm.example/hook"}`, http.StatusForbidden},
+		"Bad json":             {encoded.ManageToken, `{"kind": 1}`, http.StatusBadRequest},
+		"Unknown kind":         {encoded.ManageToken, `{"kind": "clicks", "notifier": "webhook", "target": "https://crm.example/hook"}`, http.StatusBadRequest},
+		"Visits without limit": {encoded.ManageToken, `{"kind": "visits", "window": "1h", "notifier": "webhook", "target": "https://crm.example/hook"}`, http.StatusBadRequest},
+		"Too long window":      {encoded.ManageToken, `{"kind": "visits", "threshold": 10, "window": "48h", "notifier": "webhook", "target": "https://crm.example/hook"}`, http.StatusBadRequest},
+		"Invalid duration":     {encoded.ManageToken, `{"kind": "expiring", "before": "tomorrow", "notifier": "webhook", "target": "https://crm.example/hook"}`, http.StatusBadRequest},
+		"Email not configured": {encoded.ManageToken, `{"kind": "consumed", "notifier": "email", "target": "owner@example.com"}`, http.StatusBadRequest},
+		"Invalid target":       {encoded.ManageToken, `{"kind": "consumed", "notifier": "webhook", "target": "crm"}`, http.StatusBadRequest},
+		"Private target":       {encoded.ManageToken, `{"kind": "consumed", "notifier": "webhook", "target": "http://127.0.0.1:6379/"}`, http.StatusBadRequest},
+		"Metadata target":      {encoded.ManageToken, `{"kind": "consumed", "notifier": "webhook", "target": "http://169.254.169.254/latest"}`, http.StatusBadRequest},
+		"Target scheme":        {encoded.ManageToken, `{"kind": "consumed", "notifier": "webhook", "target": "ftp://crm.example/hook"}`, http.StatusBadRequest},
+		"Success":              {encoded.ManageToken, `{"kind": "visits", "threshold": 10, "window": "1h", "notifier": "webhook", "target": "https://crm.example/hook"}`, http.StatusOK},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "POST", path, tc.token, tc.data, nil)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+		})
+	}
+
+	rules := []alert.Rule{}
+	DoRequest(t, srv, "GET", path, encoded.ManageToken, "", &rules)
+
+	if len(rules) != 1 || rules[0].Kind != alert.KindVisits || rules[0].Threshold != 10 {
+		t.Fatalf("Error! Expected visits rule, got %v", rules)
+	}
+
+	for i := 1; i < alert.MaxRules; i++ {
+		DoRequest(t, srv, "POST", path, encoded.ManageToken, `{"kind": "consumed", "notifier": "webhook", "target": "https://crm.example/hook"}`, nil)
+	}
+
+	resp := DoRequest(t, srv, "POST", path, encoded.ManageToken, `{"kind": "consumed", "notifier": "webhook", "target": "https://crm.example/hook"}`, nil)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	deletes := map[string]struct {
+		path string
+		code int
+	}{
+		"Unknown rule": {fmt.Sprintf("%s/%s", path, "0"), http.StatusNotFound},
+		"Success":      {fmt.Sprintf("%s/%s", path, rules[0].ID), http.StatusOK},
+	}
+
+	for name, tc := range deletes {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "DELETE", tc.path, encoded.ManageToken, "", nil)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+		})
+	}
+
+	id, err := s.shortener.Decode(code)
+	CheckFatal(t, err)
+
+	DoRequest(t, srv, "DELETE", "/"+code, encoded.ManageToken, "", nil)
+
+	if left, _ := s.alerts.List(id); len(left) != 0 {
+		t.Fatalf("Error! Expected rules to be removed with link, got %v", left)
+	}
+}
+
+func TestEmailAlertsAdminOnly(t *testing.T) {
+	s := GetTestAPI()
+	s.notifiers[alert.NotifierEmail] = alert.NewSMTPNotifier("127.0.0.1:25", "alerts@short.ly", "", "")
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://vk.com/sale", "expire": "10.1.2380 1:0:0"}`, encoded)
+	path := encoded.URL[strings.LastIndex(encoded.URL, "/"):] + "/alerts"
+	data := `{"kind": "consumed", "notifier": "email", "target": "owner@example.com"}`
+
+	tests := map[string]struct {
+		token string
+		code  int
+	}{
+		"Management token": {encoded.ManageToken, http.StatusForbidden},
+		"Admin token":      {testAdminToken, http.StatusOK},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "POST", path, tc.token, data, nil)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/gorilla/mux"
+)
+
+//clickCookie - cookie with last click of link, it is scoped to pixel of link
+const clickCookie = "sclid"
+
+//pixel - transparent 1x1 GIF
+var pixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+func pixelPath(code string) string {
+	return "/px/" + code + ".gif"
+}
+
+//trackClick - remember click of tracked link and add its id to destination URL, returns destination URL.
+//Tracking is disabled if CLICK_ID_PARAM is empty, errors don't break redirect
+func (s *Server) trackClick(w http.ResponseWriter, r *http.Request, item *store.Item, code string) string {
+	if s.config.ClickIDParam == "" || !item.Track {
+		return item.URL
+	}
+
+	click := events.NewID()
+
+	if err := s.conversions.Click(click, item.ID, time.Now().UTC()); err != nil {
+		s.log.Errorf("Track click error: %v", err)
+		return item.URL
+	}
+
+	// third-party cookie requires Secure, without TLS cookie works only for pixel on the same site
+	cookie := &http.Cookie{
+		Name: clickCookie, Value: click, Path: pixelPath(code), MaxAge: int(conversion.Window / time.Second),
+		HttpOnly: true, SameSite: http.SameSiteLaxMode,
+	}
+
+	if r.TLS != nil {
+		cookie.Secure, cookie.SameSite = true, http.SameSiteNoneMode
+	}
+
+	http.SetCookie(w, cookie)
+
+	return conversion.AppendClickID(item.URL, s.config.ClickIDParam, click)
+}
+
+//PixelHandler - count conversion of click from ?click= or cookie, always responds with pixel
+func (s *Server) PixelHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := s.resolve(mux.Vars(r)["id"])
+
+	if err != nil {
+		s.response404(w, r)
+		return
+	}
+
+	click := r.URL.Query().Get("click")
+
+	if c, err := r.Cookie(clickCookie); click == "" && err == nil {
+		click = c.Value
+	}
+
+	if click != "" {
+		err = s.conversions.Convert(click, id, time.Now().UTC())
+
+		if err != nil && err != conversion.ErrUnknownClick {
+			s.log.Errorf("Conversion error: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(pixel)
+}
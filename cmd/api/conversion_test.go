@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+)
+
+func TestConversionTracking(t *testing.T) {
+	s := GetTestAPI()
+	s.config.ClickIDParam = "sclid"
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://shop.example/sale?utm_source=mail", "expire": "10.1.2380 1:0:0", "track": true}`, encoded)
+	code := encoded.URL[strings.LastIndex(encoded.URL, "/")+1:]
+
+	clicks := []string{}
+	for i := 0; i < 2; i++ {
+		resp := DoRequest(t, srv, "GET", "/"+code, "", "", nil)
+		dest, err := url.Parse(resp.Header.Get("Location"))
+		CheckFatal(t, err)
+
+		if dest.Host != "shop.example" || dest.Query().Get("utm_source") != "mail" || dest.Query().Get("sclid") == "" {
+			t.Fatalf("Error! Expected destination with click id, got %v", dest)
+		}
+
+		cookies := resp.Cookies()
+
+		if len(cookies) != 1 || cookies[0].Value != dest.Query().Get("sclid") || cookies[0].Path != "/px/"+code+".gif" {
+			t.Fatalf("Error! Expected click cookie of pixel, got %v", cookies)
+		}
+
+		//test server doesn't use TLS
+		if cookies[0].Secure || cookies[0].SameSite == http.SameSiteNoneMode {
+			t.Fatalf("Error! Expected cookie without Secure, got %v", cookies[0])
+		}
+
+		clicks = append(clicks, dest.Query().Get("sclid"))
+	}
+
+	tests := []struct {
+		name   string
+		url    string
+		cookie string
+		code   int
+	}{
+		{"By click param", "/px/" + code + ".gif?click=" + clicks[0], "", http.StatusOK},
+		{"Already converted", "/px/" + code + ".gif?click=" + clicks[0], "", http.StatusOK},
+		{"Other link", "/px/Ubrm0af.gif?click=" + clicks[1], "", http.StatusOK},
+		{"Unknown click", "/px/" + code + ".gif?click=unknown", "", http.StatusOK},
+		{"By cookie", "/px/" + code + ".gif", clicks[1], http.StatusOK},
+		{"URL not found", "/px/-.gif?click=" + clicks[1], "", http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", srv.URL+tc.url, nil)
+			CheckFatal(t, err)
+
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: clickCookie, Value: tc.cookie})
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			CheckFatal(t, err)
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+
+			if tc.code == http.StatusOK && resp.Header.Get("Content-Type") != "image/gif" {
+				t.Fatalf("Error! Expected pixel, got %v", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+
+	info := &ResponseItem{}
+	DoRequest(t, srv, "GET", "/info/"+code, "", "", info)
+
+	if info.Conversions != 2 || info.ConversionRate != 1 {
+		t.Fatalf("Error! Expected 2 conversions with rate 1, got %v with rate %v", info.Conversions, info.ConversionRate)
+	}
+
+	stats := &StatsResponse{}
+	DoRequest(t, srv, "GET", "/stats/"+code, "", "", stats)
+
+	if stats.Conversions != 2 || stats.ConversionRate != 1 {
+		t.Fatalf("Error! Expected 2 conversions with rate 1, got %v with rate %v", stats.Conversions, stats.ConversionRate)
+	}
+
+	id, err := s.shortener.Decode(code)
+	CheckFatal(t, err)
+
+	DoRequest(t, srv, "DELETE", "/"+code, encoded.ManageToken, "", nil)
+
+	if n, err := s.conversions.Count(id, time.Time{}, time.Now()); n != 0 || err != nil {
+		t.Fatalf("Error! Expected conversions to be removed with link, got %v %v", n, err)
+	}
+}
+
+//countingStore - conversion.Store which counts saved clicks
+type countingStore struct {
+	conversion.Store
+	clicks int
+}
+
+func (c *countingStore) Click(id string, linkID uint64, t time.Time) error {
+	c.clicks++
+	return c.Store.Click(id, linkID, t)
+}
+
+func TestConversionTrackingIsOptional(t *testing.T) {
+	s := GetTestAPI()
+	clicks := &countingStore{Store: s.conversions}
+	s.conversions = clicks
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	resp := DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://shop.example/sale", "expire": "10.1.2380 1:0:0", "track": true}`, nil)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	s.config.ClickIDParam = "sclid"
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://shop.example/sale", "expire": "10.1.2380 1:0:0"}`, encoded)
+	code := encoded.URL[strings.LastIndex(encoded.URL, "/")+1:]
+
+	resp = DoRequest(t, srv, "GET", "/"+code, "", "", nil)
+
+	if loc := resp.Header.Get("Location"); loc != "https://shop.example/sale" || len(resp.Cookies()) != 0 {
+		t.Fatalf("Error! Expected redirect without click id, got %v %v", loc, resp.Cookies())
+	}
+
+	if clicks.clicks != 0 {
+		t.Fatalf("Error! Expected no clicks, got %v", clicks.clicks)
+	}
+}
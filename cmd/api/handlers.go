@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
 	"github.com/VladimirStepanov/urlshortener/pkg/store"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -17,6 +22,8 @@ type EncodeRequest struct {
 	URL    string `json:"url"`
 	Expire string `json:"expire"`
 	Once   bool   `json:"once"`
+	//Track - track conversions of link, requires CLICK_ID_PARAM
+	Track bool `json:"track"`
 }
 
 //EncodeURL ...
@@ -43,9 +50,60 @@ func (s *Server) EncodeURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if er.Track && s.config.ClickIDParam == "" {
+		s.ResponseJSON(w, &Response{"error", "track: conversion tracking is disabled."}, 400)
+		return
+	}
+
+	//policy and threat list are checked against normalized URL, e.g. with punycode host.
+	//Link redirects to submitted URL without stripped parameters, duplicates are found by its normalized form
+	url, err := s.normalizer.Normalize(er.URL)
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", "url: invalid url."}, 400)
+		return
+	}
+
+	target, err := s.normalizer.Strip(er.URL)
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", "url: invalid url."}, 400)
+		return
+	}
+
+	if s.policy != nil {
+		if violations := s.policy.Check(url); len(violations) > 0 {
+			s.ResponseJSON(w, &RejectResponse{"error", "url: rejected by policy.", violations}, 400)
+			return
+		}
+	}
+
+	if s.checker != nil {
+		match, err := s.checker.Check(url)
+
+		if err != nil {
+			s.serverError(w, err)
+			return
+		}
+
+		if match != nil {
+			reasons := []policy.Violation{{Rule: "threat", Reason: fmt.Sprintf("url is listed as %s", match.Threat)}}
+			s.ResponseJSON(w, &RejectResponse{"error", "url: rejected by policy.", reasons}, 400)
+			return
+		}
+	}
+
 	dt, _ := time.Parse("2.1.2006 15:4:5", er.Expire)
 
-	id, err := s.db.Save(er.URL, dt, er.Once)
+	var id uint64
+	created := true
+
+	//one-time and tracked links are never shared
+	if s.config.DedupURLs && !er.Once && !er.Track {
+		id, created, err = s.db.SaveUnique(target, dt, er.Once)
+	} else {
+		id, err = s.db.Save(target, dt, er.Once)
+	}
 
 	if err != nil {
 		if err == store.ErrExpired {
@@ -56,7 +114,82 @@ func (s *Server) EncodeURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.ResponseJSON(w, &EncodeResponse{"success", fmt.Sprintf("http://%s:%s/%s", s.config.Host, s.config.Port, s.shortener.Encode(id))}, 200)
+	if !created {
+		s.ResponseJSON(w, &EncodeResponse{Status: "success", URL: s.shortURL(s.shortener.Encode(id))}, 200)
+		return
+	}
+
+	token, hash, err := newManageToken()
+
+	if err == nil {
+		err = s.db.SetTokenHash(id, hash)
+	}
+
+	if err == nil && target != er.URL {
+		err = s.db.SetOriginalURL(id, er.URL)
+	}
+
+	if err == nil && er.Track {
+		err = s.db.SetTrack(id)
+	}
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	code := s.shortener.Encode(id)
+
+	s.emit(events.New(events.LinkCreated, id, code, target))
+
+	s.ResponseJSON(w, &EncodeResponse{"success", s.shortURL(code), token}, 200)
+}
+
+//shortURL - full short link of code
+func (s *Server) shortURL(code string) string {
+	return fmt.Sprintf("http://%s:%s/%s", s.config.Host, s.config.Port, code)
+}
+
+//resolve - id of link with code. Code which is valid in several codecs of MultiDecoder
+//is resolved to existing link, if links exist for several codecs the first codec wins and it is logged
+func (s *Server) resolve(code string) (uint64, error) {
+	m, ok := s.shortener.(*shortener.MultiDecoder)
+
+	if !ok {
+		return s.shortener.Decode(code)
+	}
+
+	ids, err := m.DecodeAll(code)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 1 {
+		return ids[0], nil
+	}
+
+	var existing []uint64
+
+	for _, id := range ids {
+		_, err = s.db.Load(id)
+
+		if err == nil {
+			existing = append(existing, id)
+		} else if err != store.ErrItemNotFound {
+			return 0, err
+		}
+	}
+
+	if len(existing) == 0 {
+		return ids[0], nil
+	}
+
+	if len(existing) > 1 {
+		s.log.Warnf("Code %s is ambiguous, links %v exist, link %d is used", code, existing, existing[0])
+	}
+
+	return existing[0], nil
 }
 
 //GetInfoHandler ...
@@ -64,9 +197,13 @@ func (s *Server) GetInfoHandler(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 
-	id, err := s.shortener.Decode(vars["id"])
+	id, err := s.resolve(vars["id"])
 
 	if err != nil {
+		if shortener.IsInvalidCode(err) {
+			s.response404(w, r)
+			return
+		}
 		s.serverError(w, err)
 		return
 	}
@@ -82,12 +219,24 @@ func (s *Server) GetInfoHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respItem := ResponseItem{
-		ID: vars["id"], BaseItem: store.BaseItem{
-			URL: item.URL, Visits: item.Visits, Expire: item.Expire, Once: item.Once,
-		},
+	respItem := newResponseItem(vars["id"], item)
+
+	respItem.UniqueVisitors, err = s.analytics.UniqueVisitors(id)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	respItem.Conversions, err = s.conversions.Count(id, time.Time{}, time.Now())
+
+	if err != nil {
+		s.serverError(w, err)
+		return
 	}
 
+	respItem.ConversionRate = conversion.Rate(respItem.Conversions, item.Visits)
+
 	s.ResponseJSON(w, respItem, 200)
 
 }
@@ -96,10 +245,14 @@ func (s *Server) GetInfoHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Server) RedirectURL(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
-	id, err := s.shortener.Decode(vars["id"])
+	id, err := s.resolve(vars["id"])
 
 	if err != nil {
-		s.response404(w, r)
+		if shortener.IsInvalidCode(err) {
+			s.ResponseJSON(w, &Response{"error", "malformed short code"}, http.StatusNotFound)
+			return
+		}
+		s.serverError(w, err)
 		return
 	}
 
@@ -114,32 +267,53 @@ func (s *Server) RedirectURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if item.Disabled {
+		if strings.HasPrefix(item.DisabledReason, moderationPrefix) {
+			s.ResponseJSON(w, &Response{"error", "link is unavailable for legal reasons"}, http.StatusUnavailableForLegalReasons)
+			return
+		}
+		s.ResponseJSON(w, &Response{"error", "link is disabled"}, http.StatusGone)
+		return
+	}
+
 	if item.Once && item.Visits > 0 {
 		s.response404(w, r)
 		return
 	}
 
+	if isPreviewRequest(r) {
+		s.previewPage(w, vars["id"], item)
+		return
+	}
+
 	err = s.db.IncVisits(id)
 	if err != nil {
 		s.response404(w, r)
 		return
 	}
 
-	http.Redirect(w, r, item.URL, http.StatusFound)
+	s.recordVisit(id, r)
+
+	e := events.New(events.LinkVisited, id, vars["id"], item.URL)
+	e.Referrer, e.UserAgent = r.Referer(), r.UserAgent()
+	s.emit(e)
+
+	http.Redirect(w, r, s.trackClick(w, r, item, vars["id"]), http.StatusFound)
 }
 
-//DeleteURL - delete URL from database
+//DeleteURL - delete URL from database, requires management token
 func (s *Server) DeleteURL(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
-	id, err := s.shortener.Decode(vars["id"])
+	item, ok := s.manageItem(w, r)
 
-	if err != nil {
-		s.response404(w, r)
+	if !ok {
 		return
 	}
 
-	_, err = s.db.Remove(id)
+	id := item.ID
+
+	item, err := s.db.Remove(id)
 
 	if err != nil {
 		if err == store.ErrItemNotFound {
@@ -150,7 +324,96 @@ func (s *Server) DeleteURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = s.board.Remove(id); err != nil {
+		s.log.Errorf("Remove from leaderboard error: %v", err)
+	}
+
+	if err = s.analytics.Remove(id); err != nil {
+		s.log.Errorf("Remove analytics error: %v", err)
+	}
+
+	if err = s.alerts.RemoveLink(id); err != nil {
+		s.log.Errorf("Remove alert rules error: %v", err)
+	}
+
+	if err = s.conversions.Remove(id); err != nil {
+		s.log.Errorf("Remove conversions error: %v", err)
+	}
+
+	s.emit(events.New(events.LinkDeleted, id, vars["id"], item.URL))
+
 	s.ResponseJSON(w, struct {
 		Status string `json:"status"`
 	}{"success"}, 200)
 }
+
+//DisableURL - pause redirects keeping visits and other data, requires management token
+func (s *Server) DisableURL(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.manageItem(w, r)
+
+	if !ok {
+		return
+	}
+
+	if item.Disabled {
+		s.ResponseJSON(w, &Response{"error", "link is already disabled"}, http.StatusConflict)
+		return
+	}
+
+	if err := s.db.Disable(item.ID, store.PausedReason); err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.emitState(item.ID, item.URL, store.PausedReason)
+
+	s.ResponseJSON(w, &Response{"success", "link is disabled"}, 200)
+}
+
+//EnableURL - resume redirects of link paused by owner, requires management token
+func (s *Server) EnableURL(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.manageItem(w, r)
+
+	if !ok {
+		return
+	}
+
+	if item.Disabled && item.DisabledReason != store.PausedReason {
+		s.ResponseJSON(w, &Response{"error", "link is disabled by moderator"}, http.StatusForbidden)
+		return
+	}
+
+	//link could be listed while it was paused, recheck may not have run yet
+	if s.checker != nil && item.Disabled {
+		match, err := s.checker.Check(item.URL)
+
+		if err != nil {
+			s.serverError(w, err)
+			return
+		}
+
+		if match != nil {
+			if err = s.db.Disable(item.ID, match.DisabledReason()); err != nil {
+				s.serverError(w, err)
+				return
+			}
+
+			s.emitState(item.ID, item.URL, match.DisabledReason())
+			s.ResponseJSON(w, &Response{"error", "link is disabled by moderator"}, http.StatusForbidden)
+			return
+		}
+	}
+
+	wasDisabled := item.Disabled
+
+	if err := s.db.Enable(item.ID); err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	if wasDisabled {
+		s.emitState(item.ID, item.URL, "")
+	}
+
+	s.ResponseJSON(w, &Response{"success", "link is enabled"}, 200)
+}
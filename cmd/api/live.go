@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	//liveRetry - EventSource reconnects after it when connection is lost
+	liveRetry = time.Second
+	//livePing - comment sent to keep idle connection open in proxies
+	livePing = 5 * time.Second
+)
+
+//listenLive - receive visits from other instances, reconnects after errors
+func (s *Server) listenLive() {
+	for {
+		err := s.relay.Listen(s.live, nil)
+
+		s.log.Errorf("Listen live visits error: %v", err)
+		time.Sleep(5 * time.Second)
+	}
+}
+
+//LiveHandler - visits of link as Server-Sent Events, requires management token of link
+func (s *Server) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.manageItem(w, r)
+
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+
+	if !ok {
+		s.serverError(w, errors.New("streaming is not supported"))
+		return
+	}
+
+	visits, cancel := s.live.Subscribe(item.ID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", liveRetry.Milliseconds())
+	flusher.Flush()
+
+	ping := time.NewTicker(livePing)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case v := <-visits:
+			data, err := json.Marshal(v)
+
+			if err != nil {
+				s.log.Errorf("Live visit error: %v", err)
+				continue
+			}
+
+			fmt.Fprintf(w, "event: visit\ndata: %s\n\n", data)
+		}
+
+		flusher.Flush()
+	}
+}
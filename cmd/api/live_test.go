@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/live"
+)
+
+func TestLiveHandler(t *testing.T) {
+	s := GetTestAPI()
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://vk.com/campaign", "expire": "10.1.2380 1:0:0"}`, encoded)
+	code := encoded.URL[strings.LastIndex(encoded.URL, "/")+1:]
+
+	if len(encoded.ManageToken) != 64 {
+		t.Fatalf("Error! Expected management token, got %v", encoded.ManageToken)
+	}
+
+	tests := map[string]struct {
+		url   string
+		token string
+		code  int
+	}{
+		"Without token":        {"/stats/" + code + "/live", "", http.StatusForbidden},
+		"Wrong token":          {"/stats/" + code + "/live", "secret", http.StatusForbidden},
+		"Token of other":       {"/stats/Ubrm0af/live", encoded.ManageToken, http.StatusForbidden},
+		"URL not found":        {"/stats/Ub/live", encoded.ManageToken, http.StatusNotFound},
+		"Admin token in query": {"/stats/" + code + "/live?token=" + testAdminToken, "", http.StatusForbidden},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "GET", tc.url, tc.token, "", nil)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+		})
+	}
+
+	resp, err := http.Get(srv.URL + "/stats/" + code + "/live?token=" + encoded.ManageToken)
+	CheckFatal(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Error! Expected event stream, got %v %v", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+
+	if !lines.Scan() || lines.Text() != "retry: 1000" {
+		t.Fatalf("Error! Expected retry hint, got %v", lines.Text())
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/"+code, nil)
+	req.Header.Set("Referer", "https://news.example/article")
+	(&http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}).Do(req)
+
+	for lines.Scan() {
+		if !strings.HasPrefix(lines.Text(), "data: ") {
+			continue
+		}
+
+		v := live.Visit{}
+		CheckFatal(t, json.Unmarshal([]byte(strings.TrimPrefix(lines.Text(), "data: ")), &v))
+
+		if v.Referrer != "news.example" || v.Time.IsZero() {
+			t.Fatalf("Error! Unexpected visit %v", v)
+		}
+		return
+	}
+
+	t.Fatalf("Error! Expected visit event")
+}
@@ -3,9 +3,21 @@ package main
 import (
 	"fmt"
 
+	alredis "github.com/VladimirStepanov/urlshortener/pkg/alert/redis"
+	anredis "github.com/VladimirStepanov/urlshortener/pkg/analytics/redis"
+	"github.com/VladimirStepanov/urlshortener/pkg/checker/hashlist"
 	"github.com/VladimirStepanov/urlshortener/pkg/config"
-	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
+	cvredis "github.com/VladimirStepanov/urlshortener/pkg/conversion/redis"
+	idredis "github.com/VladimirStepanov/urlshortener/pkg/idgen/redis"
+	lbredis "github.com/VladimirStepanov/urlshortener/pkg/leaderboard/redis"
+	liveredis "github.com/VladimirStepanov/urlshortener/pkg/live/redis"
+	modredis "github.com/VladimirStepanov/urlshortener/pkg/moderation/redis"
+	rlredis "github.com/VladimirStepanov/urlshortener/pkg/ratelimit/redis"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/codec"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/denylist"
 	"github.com/VladimirStepanov/urlshortener/pkg/store/redis"
+	"github.com/VladimirStepanov/urlshortener/pkg/stream"
+	whredis "github.com/VladimirStepanov/urlshortener/pkg/webhook/redis"
 )
 
 func main() {
@@ -15,7 +27,80 @@ func main() {
 		fmt.Println("Error while create conf instance", err)
 	}
 
-	serv, err := New(conf, redis.New(conf), base62.New())
+	pool := redis.NewPool(conf)
+
+	opts := []Option{
+		WithModeration(modredis.New(pool)),
+		WithAnalytics(anredis.New(pool)),
+		WithLeaderboard(lbredis.New(pool)),
+		WithWebhooks(whredis.New(pool)),
+		WithLiveRelay(liveredis.New(pool)),
+		WithAlerts(alredis.New(pool)),
+		WithConversions(cvredis.New(pool)),
+	}
+
+	if conf.EventStream != "" {
+		opts = append(opts, WithPublisher(stream.NewPublisher(pool, conf.EventStream, conf.EventStreamMaxLen)))
+	}
+
+	if conf.RateLimitBackend == "redis" {
+		opts = append(opts, WithLimiter(rlredis.New(pool)))
+	}
+
+	if conf.ThreatListFile != "" {
+		list, err := hashlist.Load(conf.ThreatListFile)
+
+		if err != nil {
+			fmt.Println("Error while load threat list", err)
+			return
+		}
+
+		go list.Watch(conf.ThreatListReload, nil, func(err error) {
+			fmt.Println("Error while reload threat list", err)
+		})
+
+		opts = append(opts, WithChecker(list))
+	}
+
+	short, err := codec.NewFromConfig(conf)
+
+	if err != nil {
+		fmt.Println("Error while create codec", err)
+		return
+	}
+
+	ids, err := idredis.NewFromConfig(pool, conf)
+
+	if err != nil {
+		fmt.Println("Error while create id allocator", err)
+		return
+	}
+
+	words, err := denylist.NewFromConfig(conf)
+
+	if err != nil {
+		fmt.Println("Error while load deny words", err)
+		return
+	}
+
+	ids = words.Filter(ids, short)
+
+	if conf.KeyPoolLow > 0 {
+		keys := idredis.NewKeyPool(pool, ids, conf.KeyPoolLow, conf.KeyPoolSize)
+
+		go keys.Run(conf.KeyPoolInterval, nil, func(err error) {
+			fmt.Println("Error while fill key pool", err)
+		})
+
+		opts = append(opts, WithGauge("urlshortener_key_pool_depth", "Unused ids in key pool.", func() (float64, error) {
+			depth, err := keys.Depth()
+			return float64(depth), err
+		}))
+
+		ids = keys
+	}
+
+	serv, err := New(conf, redis.New(conf, ids), short, opts...)
 
 	if err != nil {
 		fmt.Println("Error while create Server instance", err)
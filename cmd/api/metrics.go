@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//gauge - metric which value is read on every scrape
+type gauge struct {
+	name  string
+	help  string
+	value func() (float64, error)
+}
+
+//MetricsHandler - gauges in Prometheus text format, gauge which can't be read is skipped
+func (s *Server) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+
+	for _, g := range s.gauges {
+		value, err := g.value()
+
+		if err != nil {
+			s.log.Errorf("Read metric %s error: %v", g.name, err)
+			continue
+		}
+
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %v\n", g.name, g.help, g.name, g.name, value)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write([]byte(b.String()))
+}
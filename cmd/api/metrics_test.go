@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	s := GetTestAPI()
+	WithGauge("urlshortener_key_pool_depth", "Unused ids in key pool.", func() (float64, error) {
+		return 42, nil
+	})(s)
+	WithGauge("urlshortener_broken", "Gauge which can't be read.", func() (float64, error) {
+		return 0, errors.New("connection refused")
+	})(s)
+
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	CheckFatal(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusOK, resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	CheckFatal(t, err)
+
+	expected := "# HELP urlshortener_key_pool_depth Unused ids in key pool.\n# TYPE urlshortener_key_pool_depth gauge\nurlshortener_key_pool_depth 42\n"
+
+	if string(data) != expected {
+		t.Fatalf("Error! Expected %q, got %q", expected, data)
+	}
+}
@@ -1,9 +1,18 @@
 package main
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/VladimirStepanov/urlshortener/pkg/middleware"
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+	"github.com/gorilla/mux"
 )
 
 //CheckJSONRequestType ...
@@ -26,12 +35,128 @@ func (s *Server) JSONHeader(mux http.Handler) http.Handler {
 	})
 }
 
+//WriteTimeout - responses must be written in writeTimeout, server itself has no write timeout,
+//so streaming routes named streamRoute are not limited
+func (s *Server) WriteTimeout(next http.Handler) http.Handler {
+	limited := http.TimeoutHandler(next, writeTimeout, `{"status":"error","message":"timeout"}`)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil && route.GetName() == streamRoute {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limited.ServeHTTP(w, r)
+	})
+}
+
 //Log ...
 func (s *Server) Log(mux http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		lwr := &middleware.LoggerWR{W: w, StatusCode: 200}
 
 		mux.ServeHTTP(lwr, r)
-		s.log.Printf("%s request from %s to %s [status code %d]\n", r.Method, r.RemoteAddr, r.RequestURI, lwr.StatusCode)
+		// query is not logged, it can have tokens
+		s.log.Printf("%s request from %s to %s [status code %d]\n", r.Method, r.RemoteAddr, r.URL.Path, lwr.StatusCode)
 	})
 }
+
+//AdminOnly - check admin bearer token
+func (s *Server) AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if s.config.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) != 1 {
+			s.ResponseJSON(w, &Response{"error", "forbidden"}, http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+//clientIP - client address without port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+//rateLimitKey - API key if it is registered, client IP otherwise. Unknown keys are ignored,
+//otherwise client could get a new limit with every random key
+func (s *Server) rateLimitKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" && s.validAPIKey(key) {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+
+	return "ip:" + clientIP(r)
+}
+
+//validAPIKey - key is one of API_KEYS
+func (s *Server) validAPIKey(key string) bool {
+	for _, k := range s.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func seconds(d float64) string {
+	return strconv.Itoa(int(math.Ceil(d)))
+}
+
+//Route groups with separate rate limits
+const (
+	groupEncode   = "encode"
+	groupRedirect = "redirect"
+	groupAPI      = "api"
+)
+
+func (s *Server) groupLimit(group string) ratelimit.Limit {
+	switch group {
+	case groupEncode:
+		return s.config.RateLimitEncode
+	case groupRedirect:
+		return s.config.RateLimitRedirect
+	default:
+		return s.config.RateLimitAPI
+	}
+}
+
+//RateLimit - limit requests of route group per client
+func (s *Server) RateLimit(group string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := s.groupLimit(group)
+
+		if s.limiter == nil || limit.Unlimited() {
+			next(w, r)
+			return
+		}
+
+		res, err := s.limiter.Allow(group+":"+s.rateLimitKey(r), limit)
+
+		if err != nil {
+			s.log.Errorf("Rate limiter error: %v", err)
+			next(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		w.Header().Set("RateLimit-Reset", seconds(res.Reset.Seconds()))
+
+		if !res.Allowed {
+			w.Header().Set("Retry-After", seconds(res.RetryAfter.Seconds()))
+			s.ResponseJSON(w, &Response{"error", "too many requests"}, http.StatusTooManyRequests)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit/memory"
 )
 
 func TestCheckJSONRequestType(t *testing.T) {
@@ -54,3 +59,52 @@ func TestJSONHeader(t *testing.T) {
 		t.Fatalf("Expected %v, but got %v", "application/json", res.Header.Get("Content-type"))
 	}
 }
+
+func TestRateLimit(t *testing.T) {
+	srv := &Server{
+		config:  &config.Config{RateLimitAPI: ratelimit.Limit{Requests: 1, Period: time.Minute}, APIKeys: []string{"secret"}},
+		limiter: memory.New(),
+	}
+	middleWare := srv.RateLimit(groupAPI, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+
+	tests := []struct {
+		name      string
+		apiKey    string
+		code      int
+		remaining string
+	}{
+		{"First request", "", 200, "0"},
+		{"Limit exceeded", "", http.StatusTooManyRequests, "0"},
+		{"Request with API key", "secret", 200, "0"},
+		{"Unknown API key", "random-1", http.StatusTooManyRequests, "0"},
+		{"Other unknown API key", "random-2", http.StatusTooManyRequests, "0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "http://127.0.0.1", nil)
+			if tc.apiKey != "" {
+				req.Header.Set("X-API-Key", tc.apiKey)
+			}
+
+			middleWare(wr, req)
+
+			res := wr.Result()
+
+			if res.StatusCode != tc.code {
+				t.Fatalf("Error! Expected %v, got %v", tc.code, res.StatusCode)
+			}
+
+			if res.Header.Get("RateLimit-Remaining") != tc.remaining {
+				t.Fatalf("Error! Expected remaining %v, got %v", tc.remaining, res.Header.Get("RateLimit-Remaining"))
+			}
+
+			if tc.code == http.StatusTooManyRequests && res.Header.Get("Retry-After") == "" {
+				t.Fatalf("Error! Retry-After header is missing")
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/moderation"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/gorilla/mux"
+)
+
+//moderationPrefix - prefix of disabled reason for links disabled by moderator
+const moderationPrefix = "moderation: "
+
+//ReportRequest - POST data of abuse report
+type ReportRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+}
+
+//BulkRequest - links for bulk moderation action, selected by codes or destination domain
+type BulkRequest struct {
+	Codes  []string `json:"codes"`
+	Domain string   `json:"domain"`
+	Reason string   `json:"reason"`
+}
+
+//BulkResponse ...
+type BulkResponse struct {
+	Status   string `json:"status"`
+	Affected int    `json:"affected"`
+}
+
+//ReportEntry - reported link in moderation list
+type ReportEntry struct {
+	ResponseItem
+	Reports int                 `json:"reports"`
+	Recent  []moderation.Report `json:"recent"`
+}
+
+//itemFromRequest - load item by id from URL, writes error response if it is not possible
+func (s *Server) itemFromRequest(w http.ResponseWriter, r *http.Request) (*store.Item, bool) {
+	id, err := s.resolve(mux.Vars(r)["id"])
+
+	if err != nil {
+		s.response404(w, r)
+		return nil, false
+	}
+
+	item, err := s.db.Load(id)
+
+	if err != nil {
+		if err == store.ErrItemNotFound {
+			s.response404(w, r)
+			return nil, false
+		}
+		s.serverError(w, err)
+		return nil, false
+	}
+
+	return item, true
+}
+
+//ReportURL - visitor flags link for moderation
+func (s *Server) ReportURL(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.itemFromRequest(w, r)
+
+	if !ok {
+		return
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	rr := ReportRequest{}
+
+	if err := dec.Decode(&rr); err != nil {
+		s.ResponseJSON(w, &Response{"error", "bad json"}, 400)
+		return
+	}
+
+	err := validation.ValidateStruct(&rr,
+		validation.Field(&rr.Reason, validation.Required.Error("is required"), validation.In(moderation.Reasons...).Error("unknown reason")),
+		validation.Field(&rr.Comment, validation.Length(0, 500).Error("is too long")),
+	)
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", err.Error()}, 400)
+		return
+	}
+
+	err = s.moderation.Report(item.ID, moderation.Report{
+		Reason: rr.Reason, Comment: rr.Comment, Time: time.Now().UTC(), Reporter: clientIP(r),
+	})
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, &Response{"success", "report is received"}, 200)
+}
+
+//ListReports - moderation queue for admins
+func (s *Server) ListReports(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	entries, err := s.moderation.List(offset, limit)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	res := make([]ReportEntry, 0, len(entries))
+
+	for _, e := range entries {
+		item, err := s.db.Load(e.ID)
+
+		if err == store.ErrItemNotFound {
+			// link is removed or expired, nothing to moderate
+			s.moderation.Resolve(e.ID)
+			continue
+		} else if err != nil {
+			s.serverError(w, err)
+			return
+		}
+
+		res = append(res, ReportEntry{newResponseItem(s.shortener.Encode(e.ID), item), e.Reports, e.Recent})
+	}
+
+	s.ResponseJSON(w, res, 200)
+}
+
+//DismissReports - remove link from moderation queue without action
+func (s *Server) DismissReports(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.itemFromRequest(w, r)
+
+	if !ok {
+		return
+	}
+
+	if err := s.moderation.Resolve(item.ID); err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, &Response{"success", "reports are dismissed"}, 200)
+}
+
+//bulkIDs - ids of links selected by BulkRequest, domain must be normalized
+func (s *Server) bulkIDs(br *BulkRequest) ([]uint64, error) {
+	var ids []uint64
+
+	for _, code := range br.Codes {
+		if id, err := s.resolve(code); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	if br.Domain == "" {
+		return ids, nil
+	}
+
+	items, err := s.db.FindByDomain(br.Domain)
+
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids, err
+}
+
+//bulkAction - apply action to links from request body
+func (s *Server) bulkAction(w http.ResponseWriter, r *http.Request, action func(id uint64, reason string) error) {
+	br := BulkRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&br); err != nil {
+		s.ResponseJSON(w, &Response{"error", "bad json"}, 400)
+		return
+	}
+
+	if len(br.Codes) == 0 && br.Domain == "" {
+		s.ResponseJSON(w, &Response{"error", "codes or domain is required"}, 400)
+		return
+	}
+
+	if br.Domain != "" {
+		domain, err := urlnorm.Domain(strings.TrimSuffix(br.Domain, "."))
+
+		if err != nil {
+			s.ResponseJSON(w, &Response{"error", "domain: invalid domain."}, 400)
+			return
+		}
+
+		br.Domain = domain
+	}
+
+	ids, err := s.bulkIDs(&br)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	affected := 0
+
+	for _, id := range ids {
+		err := action(id, br.Reason)
+
+		if err == store.ErrItemNotFound {
+			continue
+		} else if err != nil {
+			s.serverError(w, err)
+			return
+		}
+
+		if err = s.moderation.Resolve(id); err != nil {
+			s.serverError(w, err)
+			return
+		}
+
+		affected++
+	}
+
+	s.ResponseJSON(w, &BulkResponse{"success", affected}, 200)
+}
+
+//DisableLinks - disable links by codes or destination domain
+func (s *Server) DisableLinks(w http.ResponseWriter, r *http.Request) {
+	s.bulkAction(w, r, func(id uint64, reason string) error {
+		if reason == "" {
+			reason = "abuse"
+		}
+
+		if err := s.db.Disable(id, moderationPrefix+reason); err != nil {
+			return err
+		}
+
+		s.emitState(id, "", moderationPrefix+reason)
+		return nil
+	})
+}
+
+//EnableLinks - enable links by codes or destination domain
+func (s *Server) EnableLinks(w http.ResponseWriter, r *http.Request) {
+	s.bulkAction(w, r, func(id uint64, reason string) error {
+		if err := s.db.Enable(id); err != nil {
+			return err
+		}
+
+		s.emitState(id, "", "")
+		return nil
+	})
+}
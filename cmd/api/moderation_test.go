@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestReportURLHandler(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	tests := map[string]struct {
+		url  string
+		data string
+		code int
+		resp *Response
+	}{
+		"Success report":     {"/hBKm/report", `{"reason": "phishing", "comment": "fake login page"}`, 200, &Response{"success", "report is received"}},
+		"Unknown reason":     {"/hBKm/report", `{"reason": "boring"}`, 400, &Response{"error", "reason: unknown reason."}},
+		"Reason is required": {"/hBKm/report", `{"comment": "bad"}`, 400, &Response{"error", "reason: is required."}},
+		"Bad json":           {"/hBKm/report", `{"hello": "world"}`, 400, &Response{"error", "bad json"}},
+		"URL not found":      {"/Ub/report", `{"reason": "spam"}`, 404, &Response{"error", "page not found"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &Response{}
+			resp := DoRequest(t, srv, "POST", tc.url, "", tc.data, r)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+
+			if *r != *tc.resp {
+				t.Fatalf("Error! Expected response %v, got %v", tc.resp, r)
+			}
+		})
+	}
+}
+
+func TestAdminOnly(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	tests := map[string]struct {
+		token string
+		code  int
+	}{
+		"No token":      {"", http.StatusForbidden},
+		"Invalid token": {"wrong", http.StatusForbidden},
+		"Valid token":   {testAdminToken, http.StatusOK},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "GET", "/admin/reports", tc.token, "", nil)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestModerationFlow(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	DoRequest(t, srv, "POST", "/hBKm/report", "", `{"reason": "phishing"}`, nil)
+	// repeated report from the same client is not counted
+	DoRequest(t, srv, "POST", "/hBKm/report", "", `{"reason": "spam"}`, nil)
+
+	entries := []ReportEntry{}
+	DoRequest(t, srv, "GET", "/admin/reports", testAdminToken, "", &entries)
+
+	if len(entries) != 1 || entries[0].ID != "hBKm" || entries[0].Reports != 1 || entries[0].URL != reportedItem.URL {
+		t.Fatalf("Error! Unexpected moderation list %v", entries)
+	}
+
+	bulk := &BulkResponse{}
+
+	if resp := DoRequest(t, srv, "POST", "/admin/links/disable", testAdminToken, `{"domain": "-reported.example"}`, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	DoRequest(t, srv, "POST", "/admin/links/disable", testAdminToken, `{"domain": "Reported.Example", "reason": "phishing"}`, bulk)
+
+	if bulk.Affected != 1 {
+		t.Fatalf("Error! Expected 1 disabled link, got %v", bulk.Affected)
+	}
+
+	resp := DoRequest(t, srv, "GET", "/hBKm", "", "", nil)
+
+	if resp.StatusCode != http.StatusUnavailableForLegalReasons {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusUnavailableForLegalReasons, resp.StatusCode)
+	}
+
+	entries = []ReportEntry{}
+	DoRequest(t, srv, "GET", "/admin/reports", testAdminToken, "", &entries)
+
+	if len(entries) != 0 {
+		t.Fatalf("Error! Moderation list must be empty after action, got %v", entries)
+	}
+
+	DoRequest(t, srv, "POST", "/admin/links/enable", testAdminToken, `{"codes": ["hBKm"]}`, bulk)
+
+	resp = DoRequest(t, srv, "GET", "/hBKm", "", "", nil)
+
+	if bulk.Affected != 1 || resp.StatusCode != http.StatusFound {
+		t.Fatalf("Error! Expected enabled link, got %v %v", bulk.Affected, resp.StatusCode)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/useragent"
+)
+
+var previewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:type" content="website">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.ShortURL}}">
+<meta name="twitter:card" content="summary">
+<meta name="robots" content="noindex">
+</head>
+<body>
+<a href="{{.ShortURL}}">{{.Title}}</a>
+</body>
+</html>
+`))
+
+//purposeHeaders - prefetch and preview hints of browsers
+var purposeHeaders = []string{"Purpose", "Sec-Purpose", "X-Purpose", "X-Moz"}
+
+//isPreviewRequest - request of link previewer, crawler or browser prefetch, it must not consume link
+func isPreviewRequest(r *http.Request) bool {
+	for _, h := range purposeHeaders {
+		value := strings.ToLower(r.Header.Get(h))
+
+		if strings.Contains(value, "prefetch") || strings.Contains(value, "preview") {
+			return true
+		}
+	}
+
+	return useragent.IsCrawler(r.UserAgent())
+}
+
+//previewPage - Open Graph page with destination domain instead of redirect
+func (s *Server) previewPage(w http.ResponseWriter, code string, item *store.Item) {
+	host := "link"
+
+	if u, err := url.Parse(item.URL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	description := "Short link to " + host
+	if item.Once {
+		description += ", it can be opened only once"
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	err := previewTemplate.Execute(w, struct {
+		Title       string
+		Description string
+		ShortURL    string
+	}{host, description, s.shortURL(code)})
+
+	if err != nil {
+		s.log.Errorf("Preview page error: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsPreviewRequest(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		value  string
+		exp    bool
+	}{
+		"Browser":      {"User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.93 Safari/537.36", false},
+		"Slackbot":     {"User-Agent", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		"Purpose":      {"Purpose", "prefetch", true},
+		"Sec-Purpose":  {"Sec-Purpose", "prefetch;prerender", true},
+		"X-Moz":        {"X-Moz", "prefetch", true},
+		"X-Purpose":    {"X-Purpose", "preview", true},
+		"Other header": {"X-Requested-With", "prefetch", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set(test.header, test.value)
+
+			if res := isPreviewRequest(r); res != test.exp {
+				t.Fatalf("Expected %v, got %v", test.exp, res)
+			}
+		})
+	}
+}
+
+func TestPreviewDoesNotConsumeLink(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+
+	previews := map[string]string{
+		"User-Agent": "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+		"Purpose":    "prefetch",
+	}
+
+	for header, value := range previews {
+		req, _ := http.NewRequest("GET", srv.URL+"/jBKm", nil)
+		req.Header.Set(header, value)
+
+		resp, err := client.Do(req)
+		CheckFatal(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		CheckFatal(t, err)
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Error! Expected code %v, got %v", http.StatusOK, resp.StatusCode)
+		}
+
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Fatalf("Expected html, got %v", ct)
+		}
+
+		if !strings.Contains(string(body), `<meta property="og:title" content="preview.example">`) {
+			t.Fatalf("Expected og:title with destination domain, got %s", body)
+		}
+	}
+
+	info := &ResponseItem{}
+	DoRequest(t, srv, "GET", "/info/jBKm", "", "", info)
+
+	if info.Visits != 0 {
+		t.Fatalf("Error! Expected visits %v, got %v", 0, info.Visits)
+	}
+
+	for _, code := range []int{http.StatusFound, http.StatusNotFound} {
+		resp := DoRequest(t, srv, "GET", "/jBKm", "", "", nil)
+
+		if resp.StatusCode != code {
+			t.Fatalf("Error! Expected code %v, got %v", code, resp.StatusCode)
+		}
+	}
+}
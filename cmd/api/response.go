@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"runtime/debug"
+	"time"
 
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
 	"github.com/VladimirStepanov/urlshortener/pkg/store"
 )
 
@@ -12,12 +14,49 @@ import (
 type EncodeResponse struct {
 	Status string `json:"status"`
 	URL    string `json:"url"`
+	//ManageToken - secret of link owner, it is shown only once and it is empty for existing link
+	ManageToken string `json:"manage_token,omitempty"`
 }
 
+//Link statuses
+const (
+	statusActive    = "active"
+	statusDisabled  = "disabled"
+	statusExpired   = "expired"
+	statusExhausted = "exhausted"
+)
+
 //ResponseItem - response json data for GET request
 type ResponseItem struct {
 	ID string `json:"id"`
 	store.BaseItem
+	UniqueVisitors uint64 `json:"unique_visitors"`
+	Enabled        bool   `json:"enabled"`
+	Status         string `json:"status"`
+	//Conversions - conversions reported by pixel, rate is conversions per visit
+	Conversions    uint64  `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+//itemStatus - active, disabled, expired or exhausted (once link is already visited)
+func itemStatus(item *store.Item) string {
+	if item.Disabled {
+		return statusDisabled
+	}
+
+	if t, err := time.Parse("2.1.2006 15:4:5", item.Expire); err == nil && t.Before(time.Now()) {
+		return statusExpired
+	}
+
+	if item.Once && item.Visits > 0 {
+		return statusExhausted
+	}
+
+	return statusActive
+}
+
+func newResponseItem(code string, item *store.Item) ResponseItem {
+	return ResponseItem{ID: code, BaseItem: item.BaseItem, Enabled: !item.Disabled, Status: itemStatus(item)}
 }
 
 //Response struct for json response
@@ -26,6 +65,13 @@ type Response struct {
 	Message string `json:"message"`
 }
 
+//RejectResponse - response for URL rejected by policy
+type RejectResponse struct {
+	Status  string             `json:"status"`
+	Message string             `json:"message"`
+	Reasons []policy.Violation `json:"reasons"`
+}
+
 func (s *Server) serverError(w http.ResponseWriter, err error) {
 	s.log.Errorf("Internal error: %v %s", err, string(debug.Stack()))
 	http.Error(w, `{"status": "error", "message": "Internal server error"}`, http.StatusInternalServerError)
@@ -7,13 +7,44 @@ import (
 )
 
 func (s *Server) router() http.Handler {
+	return s.JSONHeader(s.Log(s.routes()))
+}
+
+//streamRoute - name of routes which stream response, they are not limited by writeTimeout
+const streamRoute = "stream"
+
+//routes - first segments of routes must be in denylist.Reserved
+func (s *Server) routes() *mux.Router {
 	mux := mux.NewRouter()
 
-	mux.HandleFunc("/info/{id}", s.GetInfoHandler).Methods("GET")
-	mux.HandleFunc("/encode", s.CheckJSONRequestType(s.EncodeURL)).Methods("POST")
-	mux.HandleFunc("/{id}", s.RedirectURL).Methods("GET")
-	mux.HandleFunc("/{id}", s.DeleteURL).Methods("DELETE")
+	mux.HandleFunc("/info/{id}", s.RateLimit(groupAPI, s.GetInfoHandler)).Methods("GET")
+	mux.HandleFunc("/stats/{id}", s.RateLimit(groupAPI, s.StatsHandler)).Methods("GET")
+	mux.HandleFunc("/stats/{id}/breakdown", s.RateLimit(groupAPI, s.BreakdownHandler)).Methods("GET")
+	mux.HandleFunc("/stats/{id}/live", s.RateLimit(groupAPI, s.LiveHandler)).Methods("GET").Name(streamRoute)
+	mux.HandleFunc("/top", s.RateLimit(groupAPI, s.TopHandler)).Methods("GET")
+	mux.HandleFunc("/trending", s.RateLimit(groupAPI, s.TrendingHandler)).Methods("GET")
+	mux.HandleFunc("/metrics", s.RateLimit(groupAPI, s.MetricsHandler)).Methods("GET")
+	mux.HandleFunc("/px/{id}.gif", s.RateLimit(groupRedirect, s.PixelHandler)).Methods("GET")
+	mux.HandleFunc("/encode", s.RateLimit(groupEncode, s.CheckJSONRequestType(s.EncodeURL))).Methods("POST")
+	mux.HandleFunc("/{id}", s.RateLimit(groupRedirect, s.RedirectURL)).Methods("GET")
+	mux.HandleFunc("/{id}", s.RateLimit(groupAPI, s.DeleteURL)).Methods("DELETE")
+	mux.HandleFunc("/{id}/disable", s.RateLimit(groupAPI, s.DisableURL)).Methods("POST")
+	mux.HandleFunc("/{id}/enable", s.RateLimit(groupAPI, s.EnableURL)).Methods("POST")
+	mux.HandleFunc("/{id}/report", s.RateLimit(groupAPI, s.CheckJSONRequestType(s.ReportURL))).Methods("POST")
+	mux.HandleFunc("/{id}/alerts", s.RateLimit(groupAPI, s.ListAlerts)).Methods("GET")
+	mux.HandleFunc("/{id}/alerts", s.RateLimit(groupAPI, s.CheckJSONRequestType(s.CreateAlert))).Methods("POST")
+	mux.HandleFunc("/{id}/alerts/{rule}", s.RateLimit(groupAPI, s.DeleteAlert)).Methods("DELETE")
+
+	mux.HandleFunc("/links/search", s.RateLimit(groupAPI, s.AdminOnly(s.SearchLinks))).Methods("GET")
+	mux.HandleFunc("/admin/reports", s.RateLimit(groupAPI, s.AdminOnly(s.ListReports))).Methods("GET")
+	mux.HandleFunc("/admin/reports/{id}/dismiss", s.RateLimit(groupAPI, s.AdminOnly(s.DismissReports))).Methods("POST")
+	mux.HandleFunc("/admin/links/disable", s.RateLimit(groupAPI, s.AdminOnly(s.DisableLinks))).Methods("POST")
+	mux.HandleFunc("/admin/links/enable", s.RateLimit(groupAPI, s.AdminOnly(s.EnableLinks))).Methods("POST")
+	mux.HandleFunc("/admin/webhooks", s.RateLimit(groupAPI, s.AdminOnly(s.ListWebhooks))).Methods("GET")
+	mux.HandleFunc("/admin/webhooks", s.RateLimit(groupAPI, s.AdminOnly(s.CheckJSONRequestType(s.CreateWebhook)))).Methods("POST")
+	mux.HandleFunc("/admin/webhooks/{id}", s.RateLimit(groupAPI, s.AdminOnly(s.DeleteWebhook))).Methods("DELETE")
 
 	mux.NotFoundHandler = http.HandlerFunc(s.response404)
-	return s.JSONHeader(s.Log(mux))
+	mux.Use(s.WriteTimeout)
+	return mux
 }
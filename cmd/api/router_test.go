@@ -6,8 +6,22 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/codec"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/denylist"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/feistel"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/store/teststore"
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 )
 
 func TestEncodeURLHandler(t *testing.T) {
@@ -24,6 +38,9 @@ func TestEncodeURLHandler(t *testing.T) {
 		"Expire is required":              {`{"url": "https://vk.com"}`, 400, &Response{"error", "expire: is required."}},
 		"Bad json request[bad url type]":  {`{"url": 123, "expire": "10.1.2380 1:0:0"}`, 400, &Response{"error", "bad json"}},
 		"Bad json request[unknown field]": {`{"hello": "world"}`, 400, &Response{"error", "bad json"}},
+		"Blocked domain":                  {`{"url": "https://evil.com", "expire": "10.1.2380 1:0:0"}`, 400, &Response{"error", "url: rejected by policy."}},
+		"Link to shortener":               {`{"url": "http://short.ly/Ubrm0af", "expire": "10.1.2380 1:0:0"}`, 400, &Response{"error", "url: rejected by policy."}},
+		"Malicious url":                   {`{"url": "https://malware.test/", "expire": "10.1.2380 1:0:0"}`, 400, &Response{"error", "url: rejected by policy."}},
 	}
 
 	srv := GetTestServer()
@@ -70,6 +87,8 @@ func TestGetInfoHandler(t *testing.T) {
 		"Item is found":          {"info/Ubrm0af", http.StatusOK, defaultResponse},
 		"Item not found":         {"info/notFound", http.StatusNotFound, nil},
 		"Expired item not found": {"info/h4C", http.StatusNotFound, nil},
+		"Non-canonical code":     {"info/Ubrm0afa", http.StatusNotFound, nil},
+		"Overflowing code":       {"info/qIrkgbKrQ8v", http.StatusNotFound, nil},
 	}
 
 	srv := GetTestServer()
@@ -111,6 +130,8 @@ func TestRedirectURLHandler(t *testing.T) {
 		"Success redirect":        {"Ubrm0af", http.StatusFound},
 		"URL not found":           {"Ub", http.StatusNotFound},
 		"Once is already visited": {"poPnVB", http.StatusNotFound},
+		"Link is disabled":        {"gBKm", http.StatusGone},
+		"Non-canonical code":      {"Ubrm0afa", http.StatusNotFound},
 	}
 
 	for name, tc := range tests {
@@ -159,3 +180,380 @@ func TestDeleteURLHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeURLRejectReasons(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	data := `{"url": "ftp://evil.com", "expire": "10.1.2380 1:0:0"}`
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/encode", srv.URL), bytes.NewReader([]byte(data)))
+	CheckFatal(t, err)
+	req.Header.Set("Content-type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	CheckFatal(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 400 {
+		t.Fatalf("Error! Expected code %v, got %v", 400, resp.StatusCode)
+	}
+
+	r := RejectResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	CheckFatal(t, err)
+
+	expected := []policy.Violation{
+		{Rule: "scheme", Reason: `scheme "ftp" is not allowed`},
+		{Rule: "blocklist", Reason: `domain "evil.com" is blocked`},
+	}
+
+	if !reflect.DeepEqual(expected, r.Reasons) {
+		t.Fatalf("Error! Expected reasons %v, got %v", expected, r.Reasons)
+	}
+}
+
+func TestPauseURLHandlers(t *testing.T) {
+	s := GetTestAPI()
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	const owner = "owner-token"
+	CheckFatal(t, s.db.SetTokenHash(pausedItem.ID, hashToken(owner)))
+	//item of test map is shared by tests
+	defer func() { pausedItem.TokenHash = "" }()
+
+	steps := []struct {
+		method string
+		url    string
+		token  string
+		code   int
+	}{
+		{"POST", "/iBKm/disable", "", http.StatusForbidden},
+		{"POST", "/iBKm/disable", "wrong-token", http.StatusForbidden},
+		{"POST", "/iBKm/disable", owner, http.StatusOK},
+		{"POST", "/iBKm/disable", owner, http.StatusConflict},
+		{"GET", "/iBKm", "", http.StatusGone},
+		{"POST", "/iBKm/enable", "", http.StatusForbidden},
+		{"POST", "/iBKm/enable", owner, http.StatusOK},
+		{"GET", "/iBKm", "", http.StatusFound},
+		{"POST", "/gBKm/enable", testAdminToken, http.StatusForbidden},
+		{"POST", "/Ub/disable", owner, http.StatusNotFound},
+		{"DELETE", "/iBKm", "", http.StatusForbidden},
+		{"DELETE", "/iBKm", "wrong-token", http.StatusForbidden},
+		{"DELETE", "/iBKm", owner, http.StatusOK},
+		{"GET", "/iBKm", "", http.StatusNotFound},
+	}
+
+	for _, step := range steps {
+		resp := DoRequest(t, srv, step.method, step.url, step.token, "", nil)
+
+		if resp.StatusCode != step.code {
+			t.Fatalf("Error! %s %s: expected code %v, got %v", step.method, step.url, step.code, resp.StatusCode)
+		}
+
+		if step.url == "/iBKm" && step.code == http.StatusGone {
+			info := &ResponseItem{}
+			DoRequest(t, srv, "GET", "/info/iBKm", "", "", info)
+
+			if info.Enabled || info.Status != statusDisabled || info.Visits != 7 {
+				t.Fatalf("Error! Unexpected info of paused link %v", info)
+			}
+		}
+	}
+}
+
+func TestItemStatus(t *testing.T) {
+	tests := map[string]struct {
+		item   store.BaseItem
+		status string
+	}{
+		"Active":             {store.BaseItem{Expire: "10.1.2380 1:0:0"}, statusActive},
+		"Active once":        {store.BaseItem{Expire: "10.1.2380 1:0:0", Once: true}, statusActive},
+		"Disabled":           {store.BaseItem{Expire: "10.1.2380 1:0:0", Disabled: true}, statusDisabled},
+		"Expired":            {store.BaseItem{Expire: "10.1.1994 1:0:0"}, statusExpired},
+		"Exhausted":          {store.BaseItem{Expire: "10.1.2380 1:0:0", Once: true, Visits: 1}, statusExhausted},
+		"Disabled exhausted": {store.BaseItem{Expire: "10.1.2380 1:0:0", Once: true, Visits: 1, Disabled: true}, statusDisabled},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := itemStatus(&store.Item{BaseItem: tc.item})
+
+			if res != tc.status {
+				t.Fatalf("Error! Expected %v, got %v", tc.status, res)
+			}
+		})
+	}
+}
+
+func TestResolveCodes(t *testing.T) {
+	b58, err := codec.New(codec.Base58)
+	CheckFatal(t, err)
+
+	s := GetTestAPI()
+	s.shortener = shortener.NewMultiDecoder(b58, s.shortener)
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://vk.com/new", "expire": "10.1.2380 1:0:0"}`, encoded)
+	code := encoded.URL[strings.LastIndex(encoded.URL, "/")+1:]
+
+	tests := map[string]struct {
+		code string
+		url  string
+	}{
+		"New code":             {code, "https://vk.com/new"},
+		"Existing code":        {"Ubrm0af", "https://vk.com"},
+		"Valid in both codecs": {"gBKm", "https://vk.com"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			info := &ResponseItem{}
+			resp := DoRequest(t, srv, "GET", "/info/"+tc.code, "", "", info)
+
+			if resp.StatusCode != http.StatusOK || info.URL != tc.url {
+				t.Fatalf("Error! Expected %v, got %v %v", tc.url, resp.StatusCode, info.URL)
+			}
+		})
+	}
+}
+
+func TestAmbiguousCode(t *testing.T) {
+	b58, err := codec.New(codec.Base58)
+	CheckFatal(t, err)
+
+	s := GetTestAPI()
+	s.shortener = shortener.NewMultiDecoder(b58, s.shortener)
+
+	logs := &bytes.Buffer{}
+	s.log.SetOutput(logs)
+	s.log.SetLevel(logrus.WarnLevel)
+	s.log.Formatter = &logrus.TextFormatter{}
+
+	//the code is valid in both codecs and both links exist
+	other, err := b58.Decode("hBKm")
+	CheckFatal(t, err)
+
+	items := GetTestMap()
+	items[other] = &store.Item{ID: other, BaseItem: store.BaseItem{URL: "https://vk.com/other", Expire: "10.1.2380 1:0:0"}}
+	s.db = teststore.New(items)
+
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	info := &ResponseItem{}
+	DoRequest(t, srv, "GET", "/info/hBKm", "", "", info)
+
+	if info.URL != "https://vk.com/other" {
+		t.Fatalf("Error! Expected link of the first codec, got %v", info.URL)
+	}
+
+	if !strings.Contains(logs.String(), "Code hBKm is ambiguous") {
+		t.Fatalf("Error! Expected ambiguous code to be logged, got %q", logs.String())
+	}
+}
+
+func TestRotatedKeys(t *testing.T) {
+	current, err := feistel.New(base62.New(), []byte("0123456789abcdef"))
+	CheckFatal(t, err)
+	old, err := feistel.New(base62.New(), []byte("fedcba9876543210"))
+	CheckFatal(t, err)
+
+	s := GetTestAPI()
+	s.shortener = shortener.NewMultiDecoder(current, old)
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	tests := map[string]struct {
+		code   string
+		status int
+	}{
+		"Current key": {current.Encode(defaultItem.ID), http.StatusOK},
+		"Old key":     {old.Encode(defaultItem.ID), http.StatusOK},
+		"Without key": {"Ubrm0af", http.StatusNotFound},
+		"Adjacent id": {current.Encode(defaultItem.ID + 1), http.StatusNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "GET", "/info/"+tc.code, "", "", nil)
+
+			if resp.StatusCode != tc.status {
+				t.Fatalf("Error! Expected code %v, got %v", tc.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestMalformedCode(t *testing.T) {
+	c, err := codec.New(codec.Base62, codec.CheckCharacter())
+	CheckFatal(t, err)
+
+	s := GetTestAPI()
+	s.shortener = c
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	code := c.Encode(defaultItem.ID)
+	typo := code[:2] + "x" + code[3:]
+	if typo == code {
+		typo = code[:2] + "y" + code[3:]
+	}
+
+	tests := map[string]struct {
+		code    string
+		status  int
+		message string
+	}{
+		"Mistyped code": {typo, http.StatusNotFound, "malformed short code"},
+		"URL not found": {c.Encode(defaultItem.ID + 1), http.StatusNotFound, "page not found"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := &Response{}
+			resp := DoRequest(t, srv, "GET", "/"+tc.code, "", "", res)
+
+			if resp.StatusCode != tc.status || res.Message != tc.message {
+				t.Fatalf("Error! Expected %v %v, got %v %v", tc.status, tc.message, resp.StatusCode, res.Message)
+			}
+		})
+	}
+
+	resp := DoRequest(t, srv, "GET", "/"+code, "", "", nil)
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusFound, resp.StatusCode)
+	}
+}
+
+func TestDedupURLs(t *testing.T) {
+	s := GetTestAPI()
+	s.config.DedupURLs = true
+	s.normalizer = urlnorm.New("utm_*")
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	first := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://vk.com/dedup", "expire": "10.1.2380 1:0:0"}`, first)
+
+	if first.ManageToken == "" {
+		t.Fatalf("Expected manage token for new link")
+	}
+
+	tests := map[string]struct {
+		data  string
+		equal bool
+	}{
+		"Same URL":        {`{"url": "https://vk.com/dedup", "expire": "10.1.2380 1:0:0"}`, true},
+		"Upper case host": {`{"url": "https://VK.COM/dedup", "expire": "10.01.2380 01:00:00"}`, true},
+		"Tracking params": {`{"url": "https://vk.com/dedup?utm_source=x", "expire": "10.1.2380 1:0:0"}`, true},
+		"Other expire":    {`{"url": "https://vk.com/dedup", "expire": "11.1.2380 1:0:0"}`, false},
+		"Once":            {`{"url": "https://vk.com/dedup", "expire": "10.1.2380 1:0:0", "once": true}`, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded := &EncodeResponse{}
+			resp := DoRequest(t, srv, "POST", "/encode", "", tc.data, encoded)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Error! Expected code %v, got %v", http.StatusOK, resp.StatusCode)
+			}
+
+			if equal := encoded.URL == first.URL; equal != tc.equal {
+				t.Fatalf("Expected equal URLs %v, got %v %v", tc.equal, encoded.URL, first.URL)
+			}
+
+			if tc.equal && encoded.ManageToken != "" {
+				t.Fatalf("Expected no manage token for existing link, got %v", encoded.ManageToken)
+			}
+		})
+	}
+}
+
+func TestReservedRoutes(t *testing.T) {
+	words := denylist.New()
+
+	err := GetTestAPI().routes().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+
+		if err != nil {
+			return err
+		}
+
+		segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+
+		if !strings.HasPrefix(segment, "{") && words.Allowed(segment) {
+			t.Errorf("Expected %v to be reserved", segment)
+		}
+
+		return nil
+	})
+
+	CheckFatal(t, err)
+}
+
+func TestNormalizeURL(t *testing.T) {
+	s := GetTestAPI()
+	s.normalizer = urlnorm.New("utm_*")
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	tests := map[string]struct {
+		url      string
+		target   string
+		original string
+	}{
+		"Normalized URL":  {"https://vk.com/?a=1", "https://vk.com/?a=1", ""},
+		"Changed URL":     {"https://VK.com:443/?b=2&a=1", "https://VK.com:443/?b=2&a=1", ""},
+		"Tracking params": {"https://VK.com:443/?b=2&a=1&utm_source=x", "https://VK.com:443/?b=2&a=1", "https://VK.com:443/?b=2&a=1&utm_source=x"},
+		"IDN":             {"https://пример.рф/", "https://пример.рф/", ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded := &EncodeResponse{}
+			data := fmt.Sprintf(`{"url": %q, "expire": "10.1.2380 1:0:0"}`, tc.url)
+			resp := DoRequest(t, srv, "POST", "/encode", "", data, encoded)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Error! Expected code %v, got %v", http.StatusOK, resp.StatusCode)
+			}
+
+			info := &ResponseItem{}
+			DoRequest(t, srv, "GET", "/info/"+encoded.URL[strings.LastIndex(encoded.URL, "/")+1:], "", "", info)
+
+			if info.URL != tc.target || info.OriginalURL != tc.original {
+				t.Fatalf("Expected %v %v, got %v %v", tc.target, tc.original, info.URL, info.OriginalURL)
+			}
+		})
+	}
+}
+
+func TestEnableListedPausedURL(t *testing.T) {
+	s := GetTestAPI()
+	s.db = teststore.New(map[uint64]*store.Item{
+		1: {ID: 1, BaseItem: store.BaseItem{URL: "https://malware.test/", Expire: "10.1.2380 1:0:0", Disabled: true, DisabledReason: store.PausedReason}},
+	})
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	const owner = "owner-token"
+	CheckFatal(t, s.db.SetTokenHash(1, hashToken(owner)))
+	code := s.shortener.Encode(1)
+
+	if resp := DoRequest(t, srv, "POST", "/"+code+"/enable", owner, "", nil); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusForbidden, resp.StatusCode)
+	}
+
+	item, err := s.db.Load(1)
+	CheckFatal(t, err)
+
+	if !item.Disabled || item.DisabledReason != "threat: malware" {
+		t.Fatalf("Error! Expected link disabled by threat list, got %v %q", item.Disabled, item.DisabledReason)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+)
+
+//SearchLinks - links to destination URL or to domain and its subdomains
+func (s *Server) SearchLinks(w http.ResponseWriter, r *http.Request) {
+	rawURL, domain := r.URL.Query().Get("url"), r.URL.Query().Get("domain")
+
+	if (rawURL == "") == (domain == "") {
+		s.ResponseJSON(w, &Response{"error", "either url or domain is required"}, 400)
+		return
+	}
+
+	var normalized string
+
+	if rawURL != "" {
+		var err error
+		normalized, err = s.normalizer.Normalize(rawURL)
+		domains := store.Domains(normalized)
+
+		if err != nil || len(domains) == 0 {
+			s.ResponseJSON(w, &Response{"error", "url: invalid url."}, 400)
+			return
+		}
+
+		domain = domains[0]
+	} else {
+		var err error
+		domain, err = urlnorm.Domain(strings.TrimSuffix(domain, "."))
+
+		if err != nil {
+			s.ResponseJSON(w, &Response{"error", "domain: invalid domain."}, 400)
+			return
+		}
+	}
+
+	items, err := s.db.FindByDomain(domain)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	res := make([]ResponseItem, 0, len(items))
+
+	for _, item := range items {
+		if normalized != "" {
+			if item.Normalized() != normalized {
+				continue
+			}
+		}
+
+		res = append(res, newResponseItem(s.shortener.Encode(item.ID), item))
+	}
+
+	s.ResponseJSON(w, res, 200)
+}
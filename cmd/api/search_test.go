@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSearchLinks(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	reported := GetTestAPI().shortener.Encode(reportedItem.ID)
+
+	tests := map[string]struct {
+		query string
+		token string
+		code  int
+		ids   []string
+	}{
+		"Domain":            {"domain=reported.example", testAdminToken, 200, []string{reported}},
+		"Subdomain":         {"domain=SUB.reported.example.", testAdminToken, 200, []string{reported}},
+		"Other domain":      {"domain=example", testAdminToken, 200, []string{}},
+		"URL":               {"url=" + url.QueryEscape("https://SUB.reported.example:443/page"), testAdminToken, 200, []string{reported}},
+		"Other URL":         {"url=" + url.QueryEscape("https://sub.reported.example/other"), testAdminToken, 200, []string{}},
+		"URL without host":  {"url=" + url.QueryEscape("mailto:user@reported.example"), testAdminToken, 400, nil},
+		"Without params":    {"", testAdminToken, 400, nil},
+		"Both params":       {"domain=reported.example&url=https://reported.example/", testAdminToken, 400, nil},
+		"Without admin key": {"domain=reported.example", "", http.StatusForbidden, nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var res json.RawMessage
+			resp := DoRequest(t, srv, "GET", "/links/search?"+tc.query, tc.token, "", &res)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+
+			if tc.ids == nil {
+				return
+			}
+
+			items := []ResponseItem{}
+			CheckFatal(t, json.Unmarshal(res, &items))
+
+			ids := []string{}
+			for _, item := range items {
+				ids = append(ids, item.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tc.ids) {
+				t.Fatalf("Expected %v, got %v", tc.ids, ids)
+			}
+		})
+	}
+}
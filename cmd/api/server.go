@@ -5,9 +5,28 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+	almemory "github.com/VladimirStepanov/urlshortener/pkg/alert/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	anmemory "github.com/VladimirStepanov/urlshortener/pkg/analytics/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/checker"
 	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+	cvmemory "github.com/VladimirStepanov/urlshortener/pkg/conversion/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/leaderboard"
+	lbmemory "github.com/VladimirStepanov/urlshortener/pkg/leaderboard/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/live"
+	"github.com/VladimirStepanov/urlshortener/pkg/moderation"
+	modmemory "github.com/VladimirStepanov/urlshortener/pkg/moderation/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit/memory"
 	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
 	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+	whmemory "github.com/VladimirStepanov/urlshortener/pkg/webhook/memory"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,6 +36,44 @@ type Server struct {
 	db        store.Storage
 	config    *config.Config
 	shortener shortener.Shortener
+	limiter   ratelimit.Limiter
+	policy    *policy.Engine
+	checker   checker.URLChecker
+	//normalizer - canonical form of URLs of new links
+	normalizer *urlnorm.Normalizer
+	//moderation - queue of reported links
+	moderation moderation.Queue
+	analytics  analytics.Store
+	visitors   *analytics.Fingerprinter
+	board      leaderboard.Board
+	webhooks   webhook.Store
+	dispatcher *webhook.Dispatcher
+	//publishers - receivers of link events, they are called from queue if it is set
+	publishers []events.Publisher
+	queue      *events.Queue
+	//live - subscribers of live visit feed on this instance
+	live  *live.Hub
+	relay live.Relay
+	//alerts - rules of link owners, notifiers by name, targets - policy of webhook and alert targets
+	alerts    alert.Store
+	targets   *policy.Engine
+	notifiers map[string]alert.Notifier
+	//conversions - clicks and conversions reported by pixel
+	conversions conversion.Store
+	gauges      []gauge
+}
+
+//writeTimeout - time of writing response except streaming ones, see WriteTimeout
+const writeTimeout = 15 * time.Second
+
+//Option - optional Server dependency
+type Option func(*Server)
+
+//WithLimiter - replace default in-memory rate limiter
+func WithLimiter(l ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = l
+	}
 }
 
 func getLogger(level string) (*logrus.Logger, error) {
@@ -37,25 +94,191 @@ func getLogger(level string) (*logrus.Logger, error) {
 	return log, nil
 }
 
+//WithChecker - check new and existing links against list of malicious URLs
+func WithChecker(c checker.URLChecker) Option {
+	return func(s *Server) {
+		s.checker = c
+	}
+}
+
+//WithModeration - replace default in-memory moderation queue
+func WithModeration(q moderation.Queue) Option {
+	return func(s *Server) {
+		s.moderation = q
+	}
+}
+
+//WithAnalytics - replace default in-memory visit analytics
+func WithAnalytics(a analytics.Store) Option {
+	return func(s *Server) {
+		s.analytics = a
+	}
+}
+
+//WithLeaderboard - replace default in-memory top links leaderboard
+func WithLeaderboard(b leaderboard.Board) Option {
+	return func(s *Server) {
+		s.board = b
+	}
+}
+
+//WithWebhooks - replace default in-memory webhook subscriptions and delivery queue
+func WithWebhooks(w webhook.Store) Option {
+	return func(s *Server) {
+		s.webhooks = w
+	}
+}
+
+//WithPublisher - send link events to publisher in addition to webhooks
+func WithPublisher(p events.Publisher) Option {
+	return func(s *Server) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+//WithLiveRelay - share live visit feed between instances
+func WithLiveRelay(r live.Relay) Option {
+	return func(s *Server) {
+		s.relay = r
+	}
+}
+
+//WithAlerts - replace default in-memory alert rules
+func WithAlerts(a alert.Store) Option {
+	return func(s *Server) {
+		s.alerts = a
+	}
+}
+
+//WithConversions - replace default in-memory conversions
+func WithConversions(c conversion.Store) Option {
+	return func(s *Server) {
+		s.conversions = c
+	}
+}
+
+//WithGauge - expose metric on /metrics, value is read on every request
+func WithGauge(name, help string, value func() (float64, error)) Option {
+	return func(s *Server) {
+		s.gauges = append(s.gauges, gauge{name, help, value})
+	}
+}
+
 //New ...
-func New(cfg *config.Config, dbConn store.Storage, shortener shortener.Shortener) (*Server, error) {
+func New(cfg *config.Config, dbConn store.Storage, shortener shortener.Shortener, opts ...Option) (*Server, error) {
 	log, err := getLogger(cfg.LogLevel)
 	if err != nil {
 		return nil, err
 	}
-	return &Server{log: log, db: dbConn, config: cfg, shortener: shortener}, nil
+
+	pol, err := policy.NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		log: log, db: dbConn, config: cfg, shortener: shortener,
+		limiter: memory.New(), policy: pol, moderation: modmemory.New(),
+		analytics: anmemory.New(), visitors: analytics.NewFingerprinter(cfg.VisitorSecret),
+		board: lbmemory.New(), webhooks: whmemory.New(), live: live.NewHub(), alerts: almemory.New(),
+		targets:     policy.TargetsFromConfig(cfg),
+		notifiers:   map[string]alert.Notifier{alert.NotifierWebhook: alert.NewWebhookNotifier(cfg.WebhookTimeout, cfg.AllowPrivateNetworks)},
+		conversions: cvmemory.New(), normalizer: urlnorm.NewFromConfig(cfg),
+	}
+
+	if cfg.SMTPAddr != "" {
+		s.notifiers[alert.NotifierEmail] = alert.NewSMTPNotifier(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUser, cfg.SMTPPassword)
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.dispatcher = webhook.NewDispatcher(s.webhooks, cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.AllowPrivateNetworks)
+	s.publishers = append(s.publishers, s.dispatcher)
+
+	if s.relay != nil {
+		s.publishers = append(s.publishers, s.relay)
+	} else {
+		s.publishers = append(s.publishers, s.live)
+	}
+
+	s.queue = events.NewQueue(cfg.EventQueueSize, s.publishers...)
+
+	return s, nil
 }
 
 //Start run server
 func (s *Server) Start() error {
 
+	//live visits are streamed without deadline, other responses are limited by WriteTimeout middleware
 	srv := &http.Server{
 		Handler:      s.router(),
 		Addr:         fmt.Sprintf("%s:%s", s.config.Host, s.config.Port),
-		WriteTimeout: 15 * time.Second,
+		WriteTimeout: 0,
 		ReadTimeout:  15 * time.Second,
 	}
 	s.log.Infof("Starting server on %s:%s\n", s.config.Host, s.config.Port)
 
+	//links created before domain index must be found by bulk actions and search
+	if ix, ok := s.db.(store.DomainIndexer); ok {
+		indexed, err := ix.IndexDomains()
+
+		if err != nil {
+			return err
+		}
+
+		if indexed > 0 {
+			s.log.Infof("Domain index: %d links are added", indexed)
+		}
+	}
+
+	if s.checker != nil && s.config.RecheckInterval > 0 {
+		go s.recheckLinks(s.config.RecheckInterval)
+	}
+
+	go s.queue.Run(func(e events.Event, err error) {
+		s.log.Errorf("Publish %s event error: %v", e.Type, err)
+	})
+
+	go s.dispatcher.Run(time.Second, nil, func(err error) {
+		s.log.Errorf("Webhook delivery error: %v", err)
+	})
+
+	if w, ok := s.db.(store.ExpiryWatcher); ok {
+		go s.watchExpired(w)
+	}
+
+	if s.relay != nil {
+		go s.listenLive()
+	}
+
+	if s.config.AlertInterval > 0 {
+		worker := alert.NewWorker(s.alerts, s.db, s.analytics, s.notifiers, s.shortener.Encode)
+
+		go worker.Run(s.config.AlertInterval, nil, func(err error) {
+			s.log.Errorf("Alerts error: %v", err)
+		})
+	}
+
 	return srv.ListenAndServe()
 }
+
+//recheckLinks - periodically disable links which became malicious after creation
+func (s *Server) recheckLinks(interval time.Duration) {
+	for range time.Tick(interval) {
+		disabled, err := checker.Recheck(s.checker, s.db, func(item *store.Item, reason string) {
+			s.emitState(item.ID, item.URL, reason)
+		}, func(err error) {
+			s.log.Errorf("Recheck link error: %v", err)
+		})
+
+		if err != nil {
+			s.log.Errorf("Recheck links error: %v", err)
+		}
+
+		if disabled > 0 {
+			s.log.Infof("Recheck links: %d links are disabled", disabled)
+		}
+	}
+}
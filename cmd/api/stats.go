@@ -0,0 +1,162 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+	"github.com/gorilla/mux"
+)
+
+//StatsResponse - visits time series
+type StatsResponse struct {
+	ID          string            `json:"id"`
+	Granularity string            `json:"granularity"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	Total       uint64            `json:"total"`
+	Points      []analytics.Point `json:"points"`
+	//Conversions - conversions reported from..to
+	Conversions    uint64  `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+//recordVisit - store redirect event, errors don't break redirect
+func (s *Server) recordVisit(id uint64, r *http.Request) {
+	e := analytics.Event{
+		ID:        id,
+		Time:      time.Now().UTC(),
+		Referrer:  r.Referer(),
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r),
+	}
+	e.Visitor = s.visitors.Fingerprint(e.IP, e.UserAgent, e.Time)
+
+	err := s.analytics.Record(e)
+
+	if err != nil {
+		s.log.Errorf("Record visit error: %v", err)
+	}
+
+	if err = s.board.Hit(id, e.Time); err != nil {
+		s.log.Errorf("Leaderboard hit error: %v", err)
+	}
+}
+
+//parseTime - RFC3339 time from query or default value
+func parseTime(r *http.Request, name string, def time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+
+	if value == "" {
+		return def, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+//StatsHandler - visits of link in time buckets
+func (s *Server) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.itemFromRequest(w, r)
+
+	if !ok {
+		return
+	}
+
+	name := r.URL.Query().Get("granularity")
+	if name == "" {
+		name = analytics.Hour.Name
+	}
+
+	g, err := analytics.ParseGranularity(name)
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", "granularity: must be minute, hour or day."}, 400)
+		return
+	}
+
+	to, err := parseTime(r, "to", time.Now().UTC())
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", "to: invalid date."}, 400)
+		return
+	}
+
+	from, err := parseTime(r, "from", to.Add(-24*time.Hour))
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", "from: invalid date."}, 400)
+		return
+	}
+
+	if err = analytics.CheckRange(g, from, to); err != nil {
+		s.ResponseJSON(w, &Response{"error", err.Error()}, 400)
+		return
+	}
+
+	points, err := s.analytics.Series(item.ID, g, from, to)
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	res := &StatsResponse{ID: mux.Vars(r)["id"], Granularity: g.Name, From: from.UTC(), To: to.UTC(), Points: points}
+
+	for _, p := range points {
+		res.Total += p.Visits
+	}
+
+	if res.Conversions, err = s.conversions.Count(item.ID, from, to); err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	res.ConversionRate = conversion.Rate(res.Conversions, res.Total)
+
+	s.ResponseJSON(w, res, 200)
+}
+
+//BreakdownResponse - top values of every breakdown dimension
+type BreakdownResponse struct {
+	ID        string            `json:"id"`
+	Referrers []analytics.Count `json:"referrers"`
+	Browsers  []analytics.Count `json:"browsers"`
+	OS        []analytics.Count `json:"os"`
+	Devices   []analytics.Count `json:"devices"`
+}
+
+//BreakdownHandler - top referring domains, browsers, OS and device classes of link
+func (s *Server) BreakdownHandler(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.itemFromRequest(w, r)
+
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if err != nil || limit <= 0 || limit > analytics.MaxBreakdown {
+		limit = 10
+	}
+
+	res := &BreakdownResponse{ID: mux.Vars(r)["id"]}
+
+	for _, d := range []struct {
+		name string
+		dst  *[]analytics.Count
+	}{
+		{analytics.DimReferrer, &res.Referrers},
+		{analytics.DimBrowser, &res.Browsers},
+		{analytics.DimOS, &res.OS},
+		{analytics.DimDevice, &res.Devices},
+	} {
+		if *d.dst, err = s.analytics.Breakdown(item.ID, d.name, limit); err != nil {
+			s.serverError(w, err)
+			return
+		}
+	}
+
+	s.ResponseJSON(w, res, 200)
+}
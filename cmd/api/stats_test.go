@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+)
+
+func TestStatsHandler(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	DoRequest(t, srv, "GET", "/Ubrm0af", "", "", nil)
+	DoRequest(t, srv, "GET", "/Ubrm0af", "", "", nil)
+
+	info := &ResponseItem{}
+	DoRequest(t, srv, "GET", "/info/Ubrm0af", "", "", info)
+
+	if info.UniqueVisitors != 1 {
+		t.Fatalf("Error! Expected 1 unique visitor, got %v", info.UniqueVisitors)
+	}
+
+	tests := map[string]struct {
+		url    string
+		code   int
+		total  uint64
+		points int
+	}{
+		"Default period":      {"/stats/Ubrm0af", http.StatusOK, 2, 25},
+		"Minutes":             {"/stats/Ubrm0af?granularity=minute&from=2020-10-04T17:00:00Z&to=2020-10-04T17:59:59Z", http.StatusOK, 0, 60},
+		"Unknown granularity": {"/stats/Ubrm0af?granularity=week", http.StatusBadRequest, 0, 0},
+		"Invalid date":        {"/stats/Ubrm0af?from=yesterday", http.StatusBadRequest, 0, 0},
+		"Too many points":     {"/stats/Ubrm0af?granularity=minute&from=2020-10-04T00:00:00Z&to=2020-10-06T00:00:00Z", http.StatusBadRequest, 0, 0},
+		"URL not found":       {"/stats/Ub", http.StatusNotFound, 0, 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := &StatsResponse{}
+			resp := DoRequest(t, srv, "GET", tc.url, "", "", res)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+
+			if tc.code == http.StatusOK && (res.Total != tc.total || len(res.Points) != tc.points) {
+				t.Fatalf("Error! Expected %v visits in %v points, got %v in %v", tc.total, tc.points, res.Total, len(res.Points))
+			}
+		})
+	}
+}
+
+func TestBreakdownHandler(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	req, err := http.NewRequest("GET", srv.URL+"/Ubrm0af", nil)
+	CheckFatal(t, err)
+	req.Header.Set("Referer", "https://www.google.com/search")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:81.0) Gecko/20100101 Firefox/81.0")
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	CheckFatal(t, err)
+	resp.Body.Close()
+
+	res := &BreakdownResponse{}
+	resp = DoRequest(t, srv, "GET", "/stats/Ubrm0af/breakdown", "", "", res)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Error! Expected code %v, got %v", http.StatusOK, resp.StatusCode)
+	}
+
+	expected := &BreakdownResponse{
+		ID:        "Ubrm0af",
+		Referrers: []analytics.Count{{Name: "google.com", Count: 1}},
+		Browsers:  []analytics.Count{{Name: "Firefox", Count: 1}},
+		OS:        []analytics.Count{{Name: "Linux", Count: 1}},
+		Devices:   []analytics.Count{{Name: "desktop", Count: 1}},
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Error! Expected %v, got %v", expected, res)
+	}
+}
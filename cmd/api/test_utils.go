@@ -1,14 +1,32 @@
 package main
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+	almemory "github.com/VladimirStepanov/urlshortener/pkg/alert/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	anmemory "github.com/VladimirStepanov/urlshortener/pkg/analytics/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/checker"
 	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	cvmemory "github.com/VladimirStepanov/urlshortener/pkg/conversion/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	lbmemory "github.com/VladimirStepanov/urlshortener/pkg/leaderboard/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/live"
+	modmemory "github.com/VladimirStepanov/urlshortener/pkg/moderation/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
 	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
 	"github.com/VladimirStepanov/urlshortener/pkg/store"
 	"github.com/VladimirStepanov/urlshortener/pkg/store/teststore"
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+	whmemory "github.com/VladimirStepanov/urlshortener/pkg/webhook/memory"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,11 +37,32 @@ var (
 
 	defaultItemWithAlreadyOnce = &store.Item{ID: 25433331007, BaseItem: store.BaseItem{URL: "https://vk.com", Visits: 1, Expire: "10.1.2380 1:0:0", Once: true}}
 
-	defaultResponse = &ResponseItem{"Ubrm0af", store.BaseItem{URL: "https://vk.com", Visits: 100, Expire: "10.1.2380 1:0:0", Once: false}}
+	defaultResponse = &ResponseItem{"Ubrm0af", store.BaseItem{URL: "https://vk.com", Visits: 100, Expire: "10.1.2380 1:0:0", Once: false}, 0, true, "active", 0, 0}
 
 	expiredItem = &store.Item{ID: 111111, BaseItem: store.BaseItem{URL: "https://vk.com", Visits: 100, Expire: "10.1.1994 1:0:0", Once: true}}
+
+	disabledItem = &store.Item{ID: 3000000, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: "10.1.2380 1:0:0", Disabled: true, DisabledReason: "threat: malware"}}
+
+	reportedItem = &store.Item{ID: 3000001, BaseItem: store.BaseItem{URL: "https://sub.reported.example/page", Expire: "10.1.2380 1:0:0"}}
+
+	pausedItem = &store.Item{ID: 3000002, BaseItem: store.BaseItem{URL: "https://vk.com", Visits: 7, Expire: "10.1.2380 1:0:0"}}
+
+	previewItem = &store.Item{ID: 3000003, BaseItem: store.BaseItem{URL: "https://preview.example/article", Expire: "10.1.2380 1:0:0", Once: true}}
 )
 
+const testAdminToken = "admin-token"
+
+//testChecker - URLChecker which flags URLs from map
+type testChecker map[string]string
+
+//Check ...
+func (c testChecker) Check(rawURL string) (*checker.Match, error) {
+	if threat, ok := c[rawURL]; ok {
+		return &checker.Match{Threat: threat, Expression: rawURL}, nil
+	}
+	return nil, nil
+}
+
 //CheckFatal - check err. if it not nil, call t.Fatal
 func CheckFatal(t *testing.T, err error) {
 	if err != nil {
@@ -31,6 +70,32 @@ func CheckFatal(t *testing.T, err error) {
 	}
 }
 
+//DoRequest - send request to test server and decode JSON response into v if it is not nil
+func DoRequest(t *testing.T, srv *httptest.Server, method, path, token, data string, v interface{}) *http.Response {
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(data))
+	CheckFatal(t, err)
+
+	req.Header.Set("Content-type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+
+	resp, err := client.Do(req)
+	CheckFatal(t, err)
+	defer resp.Body.Close()
+
+	if v != nil {
+		CheckFatal(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+
+	return resp
+}
+
 //GetTestMap ...
 func GetTestMap() map[uint64]*store.Item {
 	return map[uint64]*store.Item{
@@ -38,16 +103,36 @@ func GetTestMap() map[uint64]*store.Item {
 		expiredItem.ID:                expiredItem,
 		defaultItemWithAlreadyOnce.ID: defaultItemWithAlreadyOnce,
 		deleteItem.ID:                 deleteItem,
+		disabledItem.ID:               disabledItem,
+		reportedItem.ID:               reportedItem,
+		pausedItem.ID:                 pausedItem,
+		previewItem.ID:                previewItem,
 	}
 }
 
-//GetTestServer ...
-func GetTestServer() *httptest.Server {
+//GetTestAPI - server with in-memory dependencies
+func GetTestAPI() *Server {
 	log := &logrus.Logger{}
 	store := teststore.New(GetTestMap())
-	conf := &config.Config{}
-	s := &Server{log, store, conf, base62.New()}
+	conf := &config.Config{AdminToken: testAdminToken}
+	pol := policy.New(policy.Schemes("http", "https"), policy.SelfHosts("short.ly"), policy.NewBlocklist("evil.com"))
+	chk := testChecker{"https://malware.test/": "malware"}
+	s := &Server{
+		log: log, db: store, config: conf, shortener: base62.New(),
+		policy: pol, checker: chk, moderation: modmemory.New(),
+		analytics: anmemory.New(), visitors: analytics.NewFingerprinter("secret"),
+		board: lbmemory.New(), webhooks: whmemory.New(), live: live.NewHub(), alerts: almemory.New(),
+		targets:     policy.New(policy.Schemes("http", "https"), policy.PrivateNetworks(nil)),
+		notifiers:   map[string]alert.Notifier{alert.NotifierWebhook: alert.NewWebhookNotifier(time.Second, false)},
+		conversions: cvmemory.New(), normalizer: urlnorm.New(),
+	}
+	s.dispatcher = webhook.NewDispatcher(s.webhooks, time.Second, 3, false)
+	s.publishers = []events.Publisher{s.dispatcher, s.live}
 	s.log.SetOutput(ioutil.Discard)
-	srv := httptest.NewServer(s.router())
-	return srv
+	return s
+}
+
+//GetTestServer ...
+func GetTestServer() *httptest.Server {
+	return httptest.NewServer(GetTestAPI().router())
 }
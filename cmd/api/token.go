@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+)
+
+//newManageToken - random management token of link and its hash which is stored
+func newManageToken() (string, string, error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(b)
+
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+//bearerToken - token from Authorization header
+func bearerToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return strings.TrimPrefix(h, "Bearer ")
+	}
+
+	return ""
+}
+
+//requestToken - bearer token or token from query, EventSource can't set headers
+func requestToken(r *http.Request) string {
+	if token := bearerToken(r); token != "" {
+		return token
+	}
+
+	return r.URL.Query().Get("token")
+}
+
+//isAdmin - request has admin token. It is accepted only from header, so it doesn't get into URLs and logs
+func (s *Server) isAdmin(r *http.Request) bool {
+	admin := bearerToken(r)
+
+	return admin != "" && s.config.AdminToken != "" && subtle.ConstantTimeCompare([]byte(admin), []byte(s.config.AdminToken)) == 1
+}
+
+//canManage - request has management token of item or admin token
+func (s *Server) canManage(r *http.Request, item *store.Item) bool {
+	if s.isAdmin(r) {
+		return true
+	}
+
+	token := requestToken(r)
+
+	return token != "" && item.TokenHash != "" && subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(item.TokenHash)) == 1
+}
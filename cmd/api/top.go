@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/leaderboard"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+)
+
+//TopEntry - link in leaderboard with visits in window or trending score.
+//Leaderboard is public, so destination URL and other link fields are not shown
+type TopEntry struct {
+	ID     string  `json:"id"`
+	Visits uint64  `json:"visits"`
+	Score  float64 `json:"score"`
+}
+
+//topLimit - limit from query, 10 by default
+func topLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	if err != nil || limit <= 0 || limit > leaderboard.MaxTop {
+		limit = 10
+	}
+
+	return limit
+}
+
+//TopHandler - links with most visits in window, all the time by default
+func (s *Server) TopHandler(w http.ResponseWriter, r *http.Request) {
+	var window time.Duration
+
+	if value := r.URL.Query().Get("window"); value != "" {
+		var err error
+
+		if window, err = time.ParseDuration(value); err == nil {
+			err = leaderboard.CheckWindow(window)
+		}
+
+		if err != nil || window == 0 {
+			s.ResponseJSON(w, &Response{"error", leaderboard.ErrInvalidWindow.Error()}, 400)
+			return
+		}
+	}
+
+	limit := topLimit(r)
+
+	// removed and disabled links are skipped, so take a few more
+	entries, err := s.board.Top(window, 2*limit, time.Now())
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.responseTop(w, entries, limit)
+}
+
+//TrendingHandler - links with most visits in last hours, recent visits weigh more
+func (s *Server) TrendingHandler(w http.ResponseWriter, r *http.Request) {
+	limit := topLimit(r)
+
+	entries, err := s.board.Trending(2*limit, time.Now())
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.responseTop(w, entries, limit)
+}
+
+func (s *Server) responseTop(w http.ResponseWriter, entries []leaderboard.Entry, limit int) {
+	res := make([]TopEntry, 0, limit)
+	removed := []uint64{}
+
+	for _, e := range entries {
+		if len(res) == limit {
+			break
+		}
+
+		item, err := s.db.Load(e.ID)
+
+		if err == store.ErrItemNotFound {
+			// link is removed or expired
+			removed = append(removed, e.ID)
+			continue
+		} else if err != nil {
+			s.serverError(w, err)
+			return
+		}
+
+		if item.Disabled {
+			continue
+		}
+
+		res = append(res, TopEntry{s.shortener.Encode(e.ID), item.Visits, e.Score})
+	}
+
+	if err := s.board.Remove(removed...); err != nil {
+		s.log.Errorf("Remove from leaderboard error: %v", err)
+	}
+
+	s.ResponseJSON(w, res, 200)
+}
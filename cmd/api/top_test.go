@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTopHandlers(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	DoRequest(t, srv, "GET", "/hBKm", "", "", nil)
+	DoRequest(t, srv, "GET", "/hBKm", "", "", nil)
+	DoRequest(t, srv, "GET", "/Ubrm0af", "", "", nil)
+
+	tests := map[string]struct {
+		url  string
+		code int
+		ids  []string
+	}{
+		"All time":       {"/top", http.StatusOK, []string{"hBKm", "Ubrm0af"}},
+		"Day":            {"/top?window=24h", http.StatusOK, []string{"hBKm", "Ubrm0af"}},
+		"Limit":          {"/top?window=24h&limit=1", http.StatusOK, []string{"hBKm"}},
+		"Trending":       {"/trending", http.StatusOK, []string{"hBKm", "Ubrm0af"}},
+		"Short window":   {"/top?window=5m", http.StatusBadRequest, nil},
+		"Invalid window": {"/top?window=day", http.StatusBadRequest, nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var res []TopEntry
+
+			if tc.code != http.StatusOK {
+				resp := DoRequest(t, srv, "GET", tc.url, "", "", nil)
+
+				if resp.StatusCode != tc.code {
+					t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+				}
+				return
+			}
+
+			resp := DoRequest(t, srv, "GET", tc.url, "", "", &res)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+
+			if len(res) != len(tc.ids) {
+				t.Fatalf("Error! Expected %v, got %v", tc.ids, res)
+			}
+
+			for i, id := range tc.ids {
+				if res[i].ID != id {
+					t.Fatalf("Error! Expected %v, got %v", tc.ids, res)
+				}
+			}
+		})
+	}
+}
+
+func TestTopHidesURL(t *testing.T) {
+	srv := GetTestServer()
+	defer srv.Close()
+
+	DoRequest(t, srv, "GET", "/hBKm", "", "", nil)
+
+	var res []map[string]interface{}
+
+	DoRequest(t, srv, "GET", "/top", "", "", &res)
+
+	if len(res) == 0 {
+		t.Fatalf("Error! Expected top links, got %v", res)
+	}
+
+	for _, e := range res {
+		if _, ok := e["url"]; ok {
+			t.Fatalf("Error! Expected no url, got %v", e)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/gorilla/mux"
+)
+
+//WebhookRequest - POST data of new webhook subscription, secret is generated if it is empty
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+//emit - send event to all publishers in background, errors don't break request.
+//Publishers are called directly if there is no queue
+func (s *Server) emit(e events.Event) {
+	if s.queue != nil {
+		if err := s.queue.Publish(e); err != nil {
+			s.log.Errorf("Publish %s event error: %v", e.Type, err)
+		}
+		return
+	}
+
+	for _, p := range s.publishers {
+		if err := p.Publish(e); err != nil {
+			s.log.Errorf("Publish %s event error: %v", e.Type, err)
+		}
+	}
+}
+
+//emitState - link.disabled event with reason or link.enabled if reason is empty
+func (s *Server) emitState(id uint64, url, reason string) {
+	typ := events.LinkEnabled
+	if reason != "" {
+		typ = events.LinkDisabled
+	}
+
+	e := events.New(typ, id, s.shortener.Encode(id), url)
+	e.Reason = reason
+	s.emit(e)
+}
+
+//watchExpired - emit link.expired events, reconnects after errors
+func (s *Server) watchExpired(w store.ExpiryWatcher) {
+	for {
+		err := w.WatchExpired(nil, func(id uint64) {
+			s.emit(events.New(events.LinkExpired, id, s.shortener.Encode(id), ""))
+
+			if err := s.board.Remove(id); err != nil {
+				s.log.Errorf("Remove from leaderboard error: %v", err)
+			}
+		})
+
+		s.log.Errorf("Watch expired links error: %v", err)
+		time.Sleep(5 * time.Second)
+	}
+}
+
+//CreateWebhook - subscribe URL to link events
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	wr := WebhookRequest{}
+
+	if err := dec.Decode(&wr); err != nil {
+		s.ResponseJSON(w, &Response{"error", "bad json"}, 400)
+		return
+	}
+
+	err := validation.ValidateStruct(&wr,
+		validation.Field(&wr.URL, validation.Required.Error("is required"), is.URL.Error("invalid url")),
+		validation.Field(&wr.Events, validation.Required.Error("is required"), validation.Each(validation.In(events.Types...).Error("unknown event"))),
+		validation.Field(&wr.Secret, validation.Length(16, 256).Error("must be from 16 to 256 characters")),
+	)
+
+	if err != nil {
+		s.ResponseJSON(w, &Response{"error", err.Error()}, 400)
+		return
+	}
+
+	// the server calls webhook itself, so it can't point to internal services
+	if s.targets != nil {
+		if violations := s.targets.Check(wr.URL); len(violations) > 0 {
+			s.ResponseJSON(w, &RejectResponse{"error", "url: rejected by policy.", violations}, 400)
+			return
+		}
+	}
+
+	sub := webhook.NewSubscription(wr.URL, wr.Events, wr.Secret)
+
+	if err = s.webhooks.Subscribe(sub); err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, sub, 200)
+}
+
+//ListWebhooks - subscriptions without secrets
+func (s *Server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.webhooks.Subscriptions()
+
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+
+	s.ResponseJSON(w, subs, 200)
+}
+
+//DeleteWebhook - unsubscribe, pending deliveries are dropped
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	err := s.webhooks.Unsubscribe(mux.Vars(r)["id"])
+
+	if err != nil {
+		if err == webhook.ErrNotFound {
+			s.response404(w, r)
+			return
+		}
+		s.serverError(w, err)
+		return
+	}
+
+	s.ResponseJSON(w, &Response{"success", "webhook is deleted"}, 200)
+}
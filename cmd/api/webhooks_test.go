@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+)
+
+func TestWebhookHandlers(t *testing.T) {
+	received := make(chan events.Event, 10)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := events.Event{}
+		json.NewDecoder(r.Body).Decode(&e)
+		received <- e
+	}))
+	defer receiver.Close()
+
+	s := GetTestAPI()
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	tests := map[string]struct {
+		token string
+		data  string
+		code  int
+	}{
+		"Without token":   {"", `{"url": "http://127.0.0.1/hook", "events": ["link.created"]}`, http.StatusForbidden},
+		"Unknown event":   {testAdminToken, `{"url": "http://127.0.0.1/hook", "events": ["link.updated"]}`, http.StatusBadRequest},
+		"Events required": {testAdminToken, `{"url": "http://127.0.0.1/hook"}`, http.StatusBadRequest},
+		"Short secret":    {testAdminToken, `{"url": "http://127.0.0.1/hook", "events": ["link.created"], "secret": "123"}`, http.StatusBadRequest},
+		"Invalid url":     {testAdminToken, `{"url": "hook", "events": ["link.created"]}`, http.StatusBadRequest},
+		"Bad json":        {testAdminToken, `{"hello": "world"}`, http.StatusBadRequest},
+		"Private target":  {testAdminToken, `{"url": "http://127.0.0.1/hook", "events": ["link.created"]}`, http.StatusBadRequest},
+		"Metadata target": {testAdminToken, `{"url": "http://169.254.169.254/latest", "events": ["link.created"]}`, http.StatusBadRequest},
+		"Other scheme":    {testAdminToken, `{"url": "ftp://vk.com/hook", "events": ["link.created"]}`, http.StatusBadRequest},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := DoRequest(t, srv, "POST", "/admin/webhooks", tc.token, tc.data, nil)
+
+			if resp.StatusCode != tc.code {
+				t.Fatalf("Error! Expected code %v, got %v", tc.code, resp.StatusCode)
+			}
+		})
+	}
+
+	//receiver runs on loopback address
+	s.targets = policy.New(policy.Schemes("http", "https"))
+	s.dispatcher = webhook.NewDispatcher(s.webhooks, time.Second, 3, true)
+	s.publishers = []events.Publisher{s.dispatcher}
+
+	sub := &webhook.Subscription{}
+	data := `{"url": "` + receiver.URL + `", "events": ["link.created", "link.visited"]}`
+	resp := DoRequest(t, srv, "POST", "/admin/webhooks", testAdminToken, data, sub)
+
+	if resp.StatusCode != http.StatusOK || sub.ID == "" || sub.Secret == "" {
+		t.Fatalf("Error! Unexpected subscription %v, code %v", sub, resp.StatusCode)
+	}
+
+	var subs []webhook.Subscription
+	DoRequest(t, srv, "GET", "/admin/webhooks", testAdminToken, "", &subs)
+
+	if len(subs) != 1 || subs[0].ID != sub.ID || subs[0].Secret != "" {
+		t.Fatalf("Error! Expected subscription without secret, got %v", subs)
+	}
+
+	encoded := &EncodeResponse{}
+	DoRequest(t, srv, "POST", "/encode", "", `{"url": "https://vk.com/campaign", "expire": "10.1.2380 1:0:0"}`, encoded)
+	code := encoded.URL[strings.LastIndex(encoded.URL, "/")+1:]
+	DoRequest(t, srv, "GET", "/"+code, "", "", nil)
+
+	if sent, err := s.dispatcher.Deliver(); sent != 2 || err != nil {
+		t.Fatalf("Error! Expected 2 deliveries, got %v %v", sent, err)
+	}
+
+	types := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		e := <-received
+
+		if e.Link != code || e.URL != "https://vk.com/campaign" {
+			t.Fatalf("Error! Unexpected event %v", e)
+		}
+		types[e.Type] = true
+	}
+
+	if !types[events.LinkCreated] || !types[events.LinkVisited] {
+		t.Fatalf("Error! Expected created and visited events, got %v", types)
+	}
+
+	for _, code := range []int{http.StatusOK, http.StatusNotFound} {
+		resp := DoRequest(t, srv, "DELETE", "/admin/webhooks/"+sub.ID, testAdminToken, "", nil)
+
+		if resp.StatusCode != code {
+			t.Fatalf("Error! Expected code %v, got %v", code, resp.StatusCode)
+		}
+	}
+}
+
+type testPublisher []events.Event
+
+func (p *testPublisher) Publish(e events.Event) error {
+	*p = append(*p, e)
+	return nil
+}
+
+func TestStateEvents(t *testing.T) {
+	s := GetTestAPI()
+	rec := &testPublisher{}
+	s.publishers = []events.Publisher{rec}
+
+	srv := httptest.NewServer(s.router())
+	defer srv.Close()
+
+	DoRequest(t, srv, "POST", "/iBKm/disable", testAdminToken, "", nil)
+	DoRequest(t, srv, "POST", "/iBKm/enable", testAdminToken, "", nil)
+
+	if len(*rec) != 2 {
+		t.Fatalf("Error! Expected 2 events, got %v", *rec)
+	}
+
+	if e := (*rec)[0]; e.Type != events.LinkDisabled || e.Reason != store.PausedReason || e.Link != "iBKm" {
+		t.Fatalf("Error! Unexpected event %v", e)
+	}
+
+	if e := (*rec)[1]; e.Type != events.LinkEnabled || e.Reason != "" {
+		t.Fatalf("Error! Unexpected event %v", e)
+	}
+}
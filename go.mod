@@ -10,4 +10,5 @@ require (
 	github.com/gorilla/mux v1.8.0
 	github.com/joho/godotenv v1.3.0
 	github.com/sirupsen/logrus v1.7.0
+	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110
 )
@@ -0,0 +1,113 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//Kinds of rules
+const (
+	//KindVisits - visits in last Window exceed Threshold
+	KindVisits = "visits"
+	//KindConsumed - once link is opened
+	KindConsumed = "consumed"
+	//KindExpiring - link expires in less than Before
+	KindExpiring = "expiring"
+)
+
+//Kinds - all kinds of rules, can be used in validation.In
+var Kinds = []interface{}{KindVisits, KindConsumed, KindExpiring}
+
+const (
+	//MaxWindow - max window of visits rule, visits are counted in minute buckets
+	MaxWindow = 24 * time.Hour
+	//MaxBefore - max time before expiry of expiring rule
+	MaxBefore = 30 * 24 * time.Hour
+	//MaxRules - max number of rules of one link
+	MaxRules = 20
+)
+
+//Duration - time.Duration which is written in json as string, e.g. "1h0m0s"
+type Duration time.Duration
+
+//MarshalText ...
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+//UnmarshalText ...
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+//ErrNotFound ...
+var ErrNotFound = errors.New("rule not found")
+
+//Rule - condition on link activity and where to send alert when it is met
+type Rule struct {
+	ID        string   `json:"id"`
+	LinkID    uint64   `json:"-"`
+	Kind      string   `json:"kind"`
+	Threshold uint64   `json:"threshold,omitempty"`
+	Window    Duration `json:"window,omitempty"`
+	Before    Duration `json:"before,omitempty"`
+	//Notifier - name of notifier, e.g. webhook or email
+	Notifier string `json:"notifier"`
+	//Target - URL of webhook or email address
+	Target string `json:"target"`
+	//Fired - time of the last alert
+	Fired   *time.Time `json:"fired,omitempty"`
+	Created time.Time  `json:"created"`
+}
+
+//ClaimKey - alert of rule is claimed once per time of the last alert, so only one instance sends it
+func (r *Rule) ClaimKey() string {
+	var fired int64
+	if r.Fired != nil {
+		fired = r.Fired.UnixNano()
+	}
+
+	return fmt.Sprintf("%s:%d", r.ID, fired)
+}
+
+//Alert - notification about met rule
+type Alert struct {
+	Rule    string    `json:"rule"`
+	Kind    string    `json:"kind"`
+	Link    string    `json:"link"`
+	URL     string    `json:"url"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+//Store - rules of links
+type Store interface {
+	Add(r *Rule) error
+	//List - rules of link, oldest first
+	List(linkID uint64) ([]Rule, error)
+	//Walk - call fn for every rule
+	Walk(fn func(*Rule) error) error
+	Remove(linkID uint64, id string) error
+	//RemoveLink - remove all rules of link
+	RemoveLink(linkID uint64) error
+	//Fired - save time of the last alert
+	Fired(linkID uint64, id string, t time.Time) error
+	//Claim - returns true if alert of rule is not claimed yet by ClaimKey, claim expires after ttl
+	Claim(r *Rule, ttl time.Duration) (bool, error)
+	//Unclaim - release claim after failed delivery, so alert is sent again on next check
+	Unclaim(r *Rule) error
+}
+
+//Notifier - delivers alert to target of rule
+type Notifier interface {
+	Notify(target string, a *Alert) error
+}
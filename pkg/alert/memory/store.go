@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+)
+
+//Store - in-memory rules for single instance and tests
+type Store struct {
+	mu    sync.Mutex
+	rules map[uint64]map[string]alert.Rule
+	//claims - expire time by claim key
+	claims map[string]time.Time
+}
+
+//New ...
+func New() *Store {
+	return &Store{rules: map[uint64]map[string]alert.Rule{}, claims: map[string]time.Time{}}
+}
+
+//Add ...
+func (s *Store) Add(r *alert.Rule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rules[r.LinkID] == nil {
+		s.rules[r.LinkID] = map[string]alert.Rule{}
+	}
+	s.rules[r.LinkID][r.ID] = *r
+
+	return nil
+}
+
+//List ...
+func (s *Store) List(linkID uint64) ([]alert.Rule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]alert.Rule, 0, len(s.rules[linkID]))
+
+	for _, r := range s.rules[linkID] {
+		res = append(res, r)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+//Walk ...
+func (s *Store) Walk(fn func(*alert.Rule) error) error {
+	s.mu.Lock()
+	rules := []alert.Rule{}
+	for _, link := range s.rules {
+		for _, r := range link {
+			rules = append(rules, r)
+		}
+	}
+	s.mu.Unlock()
+
+	for i := range rules {
+		if err := fn(&rules[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Remove ...
+func (s *Store) Remove(linkID uint64, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rules[linkID][id]; !ok {
+		return alert.ErrNotFound
+	}
+
+	delete(s.rules[linkID], id)
+
+	return nil
+}
+
+//RemoveLink ...
+func (s *Store) RemoveLink(linkID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.rules, linkID)
+
+	return nil
+}
+
+//Fired ...
+func (s *Store) Fired(linkID uint64, id string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.rules[linkID][id]
+
+	if !ok {
+		return alert.ErrNotFound
+	}
+
+	r.Fired = &t
+	s.rules[linkID][id] = r
+
+	return nil
+}
+
+//Claim ...
+func (s *Store) Claim(r *alert.Rule, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for key, expire := range s.claims {
+		if !now.Before(expire) {
+			delete(s.claims, key)
+		}
+	}
+
+	if _, ok := s.claims[r.ClaimKey()]; ok {
+		return false, nil
+	}
+
+	s.claims[r.ClaimKey()] = now.Add(ttl)
+
+	return true, nil
+}
+
+//Unclaim ...
+func (s *Store) Unclaim(r *alert.Rule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.claims, r.ClaimKey())
+
+	return nil
+}
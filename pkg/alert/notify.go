@@ -0,0 +1,92 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
+)
+
+//Names of notifiers
+const (
+	NotifierWebhook = "webhook"
+	NotifierEmail   = "email"
+)
+
+//WebhookNotifier - POST alert as json to target URL
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+//NewWebhookNotifier - notifier which refuses to connect to private addresses unless allowPrivate is set
+func NewWebhookNotifier(timeout time.Duration, allowPrivate bool) *WebhookNotifier {
+	return &WebhookNotifier{client: policy.NewClient(timeout, allowPrivate)}
+}
+
+//Notify ...
+func (n *WebhookNotifier) Notify(target string, a *Alert) error {
+	body, err := json.Marshal(a)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(target, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+//SMTPNotifier - send alert as plain text email
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+//NewSMTPNotifier - notifier for SMTP server addr, authentication is not used if user is empty
+func NewSMTPNotifier(addr, from, user, password string) *SMTPNotifier {
+	n := &SMTPNotifier{addr: addr, from: from}
+
+	if user != "" {
+		host := addr
+		if i := strings.LastIndex(addr, ":"); i >= 0 {
+			host = addr[:i]
+		}
+		n.auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	return n
+}
+
+//header - value without line breaks
+func header(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
+
+//Notify ...
+func (n *SMTPNotifier) Notify(to string, a *Alert) error {
+	msg := &bytes.Buffer{}
+
+	fmt.Fprintf(msg, "From: %s\r\n", header(n.from))
+	fmt.Fprintf(msg, "To: %s\r\n", header(to))
+	fmt.Fprintf(msg, "Subject: %s\r\n", header(fmt.Sprintf("Alert for link %s: %s", a.Link, a.Kind)))
+	fmt.Fprintf(msg, "Date: %s\r\n", a.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(msg, "%s\r\n\r\nLink: %s\r\nURL: %s\r\n", a.Message, a.Link, a.URL)
+
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{to}, msg.Bytes())
+}
@@ -0,0 +1,119 @@
+package alert
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAlert = &Alert{Rule: "1", Kind: KindConsumed, Link: "b", URL: "https://vk.com", Message: "one-time link is opened", Time: time.Now()}
+
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan Alert, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := Alert{}
+		json.NewDecoder(r.Body).Decode(&a)
+		received <- a
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookNotifier(time.Second, true).Notify(srv.URL, testAlert); err != nil {
+		t.Fatal(err)
+	}
+
+	if a := <-received; a.Rule != testAlert.Rule || a.Message != testAlert.Message {
+		t.Fatalf("Expected %v, got %v", testAlert, a)
+	}
+
+	//test server listens on loopback
+	if err := NewWebhookNotifier(time.Second, false).Notify(srv.URL, testAlert); err == nil {
+		t.Fatal("Expected private address to be refused")
+	}
+
+	if len(received) != 0 {
+		t.Fatalf("Expected no alerts, got %v", <-received)
+	}
+}
+
+//smtpStandIn - accepts one message and sends its data to channel
+func smtpStandIn(t *testing.T) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := make(chan string, 1)
+
+	go func() {
+		defer l.Close()
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 go ahead")
+
+				msg := &strings.Builder{}
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					msg.WriteString(line)
+				}
+
+				data <- msg.String()
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return l.Addr().String(), data
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, data := smtpStandIn(t)
+
+	n := NewSMTPNotifier(addr, "alerts@short.ly", "", "")
+
+	if err := n.Notify("owner@example.com", testAlert); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-data
+
+	for _, s := range []string{"To: owner@example.com", "Subject: Alert for link b: consumed", "one-time link is opened"} {
+		if !strings.Contains(msg, s) {
+			t.Fatalf("Expected %q in message, got %q", s, msg)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+	"github.com/gomodule/redigo/redis"
+)
+
+//linksKey - set of links which have rules
+const linksKey = "alerts:links"
+
+//Store - rules of link in hash alerts:{id}, rule id is field and json is value.
+//Claims of alerts are keys alerts:claim:{claim key} which expire after ttl
+type Store struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Store {
+	return &Store{pool: pool}
+}
+
+func rulesKey(linkID uint64) string {
+	return fmt.Sprintf("alerts:%d", linkID)
+}
+
+func claimKey(r *alert.Rule) string {
+	return "alerts:claim:" + r.ClaimKey()
+}
+
+//Add ...
+func (s *Store) Add(r *alert.Rule) error {
+	data, err := json.Marshal(r)
+
+	if err != nil {
+		return err
+	}
+
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("HSET", rulesKey(r.LinkID), r.ID, data)
+	conn.Send("SADD", linksKey, r.LinkID)
+	_, err = conn.Do("EXEC")
+
+	return err
+}
+
+func (s *Store) list(conn redis.Conn, linkID uint64) ([]alert.Rule, error) {
+	values, err := redis.StringMap(conn.Do("HGETALL", rulesKey(linkID)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]alert.Rule, 0, len(values))
+
+	for _, data := range values {
+		r := alert.Rule{}
+
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			return nil, err
+		}
+
+		// link id is not written to json
+		r.LinkID = linkID
+		res = append(res, r)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+//List ...
+func (s *Store) List(linkID uint64) ([]alert.Rule, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	return s.list(conn, linkID)
+}
+
+//Walk ...
+func (s *Store) Walk(fn func(*alert.Rule) error) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	links, err := redis.Strings(conn.Do("SMEMBERS", linksKey))
+
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		linkID, err := strconv.ParseUint(link, 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		rules, err := s.list(conn, linkID)
+
+		if err != nil {
+			return err
+		}
+
+		for i := range rules {
+			if err := fn(&rules[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+//Remove ...
+func (s *Store) Remove(linkID uint64, id string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	removed, err := redis.Int(conn.Do("HDEL", rulesKey(linkID), id))
+
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return alert.ErrNotFound
+	}
+
+	return nil
+}
+
+//RemoveLink ...
+func (s *Store) RemoveLink(linkID uint64) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("DEL", rulesKey(linkID))
+	conn.Send("SREM", linksKey, linkID)
+	_, err := conn.Do("EXEC")
+
+	return err
+}
+
+//Fired ...
+func (s *Store) Fired(linkID uint64, id string, t time.Time) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	data, err := redis.Bytes(conn.Do("HGET", rulesKey(linkID), id))
+
+	if err == redis.ErrNil {
+		return alert.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	r := alert.Rule{}
+
+	if err = json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	r.Fired = &t
+
+	if data, err = json.Marshal(r); err != nil {
+		return err
+	}
+
+	_, err = conn.Do("HSET", rulesKey(linkID), id, data)
+
+	return err
+}
+
+//Claim ...
+func (s *Store) Claim(r *alert.Rule, ttl time.Duration) (bool, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := redis.String(conn.Do("SET", claimKey(r), 1, "PX", int64(ttl/time.Millisecond), "NX"))
+
+	if err == redis.ErrNil {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+//Unclaim ...
+func (s *Store) Unclaim(r *alert.Rule) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", claimKey(r))
+
+	return err
+}
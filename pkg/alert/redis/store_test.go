@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestStore() *Store {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func TestStoreRedis(t *testing.T) {
+	s := NewTestStore()
+	defer s.RemoveLink(1)
+	defer s.RemoveLink(2)
+
+	now := time.Now().UTC()
+
+	s.Add(&alert.Rule{ID: "a", LinkID: 1, Kind: alert.KindConsumed, Created: now})
+	s.Add(&alert.Rule{ID: "b", LinkID: 1, Kind: alert.KindExpiring, Created: now.Add(time.Second)})
+	s.Add(&alert.Rule{ID: "c", LinkID: 2, Kind: alert.KindConsumed, Created: now})
+
+	rules, _ := s.List(1)
+	if len(rules) != 2 || rules[0].ID != "a" || rules[1].ID != "b" {
+		t.Fatalf("Expected rules a and b, got %v", rules)
+	}
+
+	if err := s.Fired(1, "a", now); err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	s.Walk(func(r *alert.Rule) error {
+		count++
+		if r.ID == "a" && (r.Fired == nil || !r.Fired.Equal(now)) {
+			t.Fatalf("Expected fired rule, got %v", r)
+		}
+		return nil
+	})
+
+	if count != 3 {
+		t.Fatalf("Expected 3 rules, got %v", count)
+	}
+
+	if err := s.Remove(1, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Remove(1, "b"); err != alert.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", alert.ErrNotFound, err)
+	}
+
+	s.RemoveLink(2)
+
+	if rules, _ = s.List(2); len(rules) != 0 {
+		t.Fatalf("Expected no rules, got %v", rules)
+	}
+}
+
+func TestClaimRedis(t *testing.T) {
+	s := NewTestStore()
+	now := time.Now().UTC()
+	r := &alert.Rule{ID: "claimed", LinkID: 1, Kind: alert.KindVisits}
+
+	defer s.Unclaim(&alert.Rule{ID: r.ID, Fired: &now})
+	defer s.Unclaim(&alert.Rule{ID: r.ID})
+
+	steps := []struct {
+		name    string
+		fired   *time.Time
+		unclaim bool
+		claimed bool
+	}{
+		{"First claim", nil, false, true},
+		{"Already claimed", nil, false, false},
+		{"After unclaim", nil, true, true},
+		{"Next alert", &now, false, true},
+	}
+
+	for _, step := range steps {
+		r.Fired = step.fired
+
+		if step.unclaim {
+			s.Unclaim(r)
+		}
+
+		if ok, err := s.Claim(r, time.Minute); ok != step.claimed || err != nil {
+			t.Fatalf("%s: expected %v, got %v %v", step.name, step.claimed, ok, err)
+		}
+	}
+}
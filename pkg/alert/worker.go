@@ -0,0 +1,192 @@
+package alert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+)
+
+//claimTTL - time to send alert and save time of it, other instances skip the alert meanwhile
+const claimTTL = 10 * time.Minute
+
+//Worker - evaluates rules against links and visit counters and sends alerts.
+//Workers of all instances check the same rules, alert is sent by the instance which claims it
+type Worker struct {
+	rules     Store
+	db        store.Storage
+	analytics analytics.Store
+	notifiers map[string]Notifier
+	//encode - encoded URL of link
+	encode func(uint64) string
+	now    func() time.Time
+}
+
+//NewWorker ...
+func NewWorker(rules Store, db store.Storage, a analytics.Store, notifiers map[string]Notifier, encode func(uint64) string) *Worker {
+	return &Worker{rules: rules, db: db, analytics: a, notifiers: notifiers, encode: encode, now: time.Now}
+}
+
+//Check - evaluate all rules, rules of removed and expired links are removed.
+//If alert is not delivered, rule is evaluated again on next check. Returns number of sent alerts
+func (w *Worker) Check() (int, error) {
+	rules := []Rule{}
+
+	err := w.rules.Walk(func(r *Rule) error {
+		rules = append(rules, *r)
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	now := w.now().UTC()
+	items := map[uint64]*store.Item{}
+	sent := 0
+
+	var lastErr error
+
+	for i := range rules {
+		r := &rules[i]
+
+		item, ok := items[r.LinkID]
+
+		if !ok {
+			item, err = w.db.Load(r.LinkID)
+
+			if err == store.ErrItemNotFound {
+				if err := w.rules.RemoveLink(r.LinkID); err != nil {
+					lastErr = err
+				}
+			} else if err != nil {
+				return sent, err
+			}
+
+			items[r.LinkID] = item
+		}
+
+		if item == nil {
+			continue
+		}
+
+		a, err := w.evaluate(r, item, now)
+
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if a == nil {
+			continue
+		}
+
+		notifier, ok := w.notifiers[r.Notifier]
+
+		if !ok {
+			lastErr = fmt.Errorf("rule %s: unknown notifier %q", r.ID, r.Notifier)
+			continue
+		}
+
+		claimed, err := w.rules.Claim(r, claimTTL)
+
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if !claimed {
+			// other instance sends this alert
+			continue
+		}
+
+		if err = notifier.Notify(r.Target, a); err != nil {
+			lastErr = fmt.Errorf("rule %s: %v", r.ID, err)
+
+			if err := w.rules.Unclaim(r); err != nil {
+				lastErr = err
+			}
+			continue
+		}
+
+		sent++
+
+		if err = w.rules.Fired(r.LinkID, r.ID, now); err != nil {
+			lastErr = err
+		}
+	}
+
+	return sent, lastErr
+}
+
+//evaluate - alert if rule is met or nil
+func (w *Worker) evaluate(r *Rule, item *store.Item, now time.Time) (*Alert, error) {
+	var message string
+
+	switch r.Kind {
+	case KindVisits:
+		window := time.Duration(r.Window)
+
+		// the rule fires at most once per window
+		if r.Fired != nil && now.Sub(*r.Fired) < window {
+			return nil, nil
+		}
+
+		points, err := w.analytics.Series(item.ID, analytics.Minute, now.Add(-window+time.Minute), now)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var visits uint64
+		for _, p := range points {
+			visits += p.Visits
+		}
+
+		if visits <= r.Threshold {
+			return nil, nil
+		}
+
+		message = fmt.Sprintf("%d visits in last %s, threshold is %d", visits, window, r.Threshold)
+	case KindConsumed:
+		if r.Fired != nil || !item.Once || item.Visits == 0 {
+			return nil, nil
+		}
+
+		message = "one-time link is opened"
+	case KindExpiring:
+		expire, err := time.Parse("2.1.2006 15:4:5", item.Expire)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Fired != nil || now.Before(expire.Add(-time.Duration(r.Before))) {
+			return nil, nil
+		}
+
+		message = fmt.Sprintf("link expires at %s", expire.Format(time.RFC3339))
+	default:
+		return nil, fmt.Errorf("rule %s: unknown kind %q", r.ID, r.Kind)
+	}
+
+	return &Alert{Rule: r.ID, Kind: r.Kind, Link: w.encode(item.ID), URL: item.URL, Message: message, Time: now}, nil
+}
+
+//Run - check rules every interval until stop is closed
+func (w *Worker) Run(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := w.Check(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
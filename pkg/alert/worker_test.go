@@ -0,0 +1,117 @@
+package alert_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/alert"
+	"github.com/VladimirStepanov/urlshortener/pkg/alert/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	anmemory "github.com/VladimirStepanov/urlshortener/pkg/analytics/memory"
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/store/teststore"
+)
+
+type testNotifier []*alert.Alert
+
+func (n *testNotifier) Notify(target string, a *alert.Alert) error {
+	*n = append(*n, a)
+	return nil
+}
+
+func TestWorker(t *testing.T) {
+	expire := time.Now().UTC().Add(2 * time.Hour).Format("2.1.2006 15:4:5")
+	far := "10.1.2380 1:0:0"
+
+	db := teststore.New(map[uint64]*store.Item{
+		1: {ID: 1, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: far}},
+		2: {ID: 2, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: far, Once: true, Visits: 1}},
+		3: {ID: 3, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: expire}},
+		4: {ID: 4, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: far}},
+	})
+
+	stats := anmemory.New()
+	for i := 0; i < 5; i++ {
+		stats.Record(analytics.Event{ID: 1, Time: time.Now().UTC()})
+		stats.Record(analytics.Event{ID: 4, Time: time.Now().UTC()})
+	}
+
+	rules := memory.New()
+	for _, r := range []alert.Rule{
+		{ID: "visits", LinkID: 1, Kind: alert.KindVisits, Threshold: 4, Window: alert.Duration(time.Hour)},
+		{ID: "not enough visits", LinkID: 4, Kind: alert.KindVisits, Threshold: 10, Window: alert.Duration(time.Hour)},
+		{ID: "consumed", LinkID: 2, Kind: alert.KindConsumed},
+		{ID: "expiring", LinkID: 3, Kind: alert.KindExpiring, Before: alert.Duration(24 * time.Hour)},
+		{ID: "removed link", LinkID: 5, Kind: alert.KindConsumed},
+	} {
+		r.Notifier = "test"
+		rules.Add(&r)
+	}
+
+	n := &testNotifier{}
+	w := alert.NewWorker(rules, db, stats, map[string]alert.Notifier{"test": n}, func(id uint64) string {
+		return strconv.FormatUint(id, 10)
+	})
+
+	sent, err := w.Check()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent != 3 || len(*n) != 3 {
+		t.Fatalf("Expected 3 alerts, got %v %v", sent, *n)
+	}
+
+	fired := map[string]bool{}
+	for _, a := range *n {
+		fired[a.Rule] = true
+	}
+
+	for _, id := range []string{"visits", "consumed", "expiring"} {
+		if !fired[id] {
+			t.Fatalf("Expected alert of rule %v, got %v", id, fired)
+		}
+	}
+
+	// rules fire once
+	if sent, _ = w.Check(); sent != 0 {
+		t.Fatalf("Expected no alerts, got %v", sent)
+	}
+
+	if res, _ := rules.List(5); len(res) != 0 {
+		t.Fatalf("Expected rules of removed link to be removed, got %v", res)
+	}
+}
+
+func TestWorkerClaims(t *testing.T) {
+	db := teststore.New(map[uint64]*store.Item{
+		1: {ID: 1, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: "10.1.2380 1:0:0", Once: true, Visits: 1}},
+	})
+
+	rule := alert.Rule{ID: "consumed", LinkID: 1, Kind: alert.KindConsumed, Notifier: "test"}
+
+	rules := memory.New()
+	rules.Add(&rule)
+
+	n := &testNotifier{}
+	w := alert.NewWorker(rules, db, anmemory.New(), map[string]alert.Notifier{"test": n}, func(id uint64) string {
+		return strconv.FormatUint(id, 10)
+	})
+
+	//alert is claimed by other instance
+	if ok, _ := rules.Claim(&rule, time.Minute); !ok {
+		t.Fatal("Expected rule to be claimed")
+	}
+
+	if sent, err := w.Check(); sent != 0 || err != nil {
+		t.Fatalf("Expected no alerts, got %v %v", sent, err)
+	}
+
+	rules.Unclaim(&rule)
+
+	if sent, err := w.Check(); sent != 1 || err != nil {
+		t.Fatalf("Expected 1 alert, got %v %v", sent, err)
+	}
+}
@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"fmt"
+	"time"
+)
+
+//MaxPoints - maximum number of buckets in one series
+const MaxPoints = 1500
+
+var (
+	//ErrUnknownGranularity ...
+	ErrUnknownGranularity = fmt.Errorf("unknown granularity")
+	//ErrTooManyPoints ...
+	ErrTooManyPoints = fmt.Errorf("too many points, use larger granularity or shorter period")
+)
+
+//Event - single redirect
+type Event struct {
+	ID        uint64
+	Time      time.Time
+	Referrer  string
+	UserAgent string
+	IP        string
+	//Visitor - fingerprint for unique visitors counting
+	Visitor string
+}
+
+//Granularity - size of time bucket. Buckets are stored in shards, whole shard expires after retention period
+type Granularity struct {
+	Name      string
+	Step      time.Duration
+	Shard     time.Duration
+	Retention time.Duration
+}
+
+var (
+	//Minute - minute buckets for last two days
+	Minute = Granularity{"minute", time.Minute, 24 * time.Hour, 2 * 24 * time.Hour}
+	//Hour - hour buckets for last 90 days
+	Hour = Granularity{"hour", time.Hour, 30 * 24 * time.Hour, 90 * 24 * time.Hour}
+	//Day - day buckets for last two years
+	Day = Granularity{"day", 24 * time.Hour, 360 * 24 * time.Hour, 2 * 365 * 24 * time.Hour}
+
+	//Granularities - every event is counted in all of them
+	Granularities = []Granularity{Minute, Hour, Day}
+)
+
+//ParseGranularity ...
+func ParseGranularity(name string) (Granularity, error) {
+	for _, g := range Granularities {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+
+	return Granularity{}, ErrUnknownGranularity
+}
+
+func floor(t time.Time, d time.Duration) time.Time {
+	sec := int64(d / time.Second)
+	unix := t.Unix()
+
+	return time.Unix(unix-((unix%sec)+sec)%sec, 0).UTC()
+}
+
+//Bucket - start of bucket which contains t
+func (g Granularity) Bucket(t time.Time) time.Time {
+	return floor(t, g.Step)
+}
+
+//ShardOf - start of shard which contains t
+func (g Granularity) ShardOf(t time.Time) time.Time {
+	return floor(t, g.Shard)
+}
+
+//Point - visits in bucket started at Time
+type Point struct {
+	Time   time.Time `json:"time"`
+	Visits uint64    `json:"visits"`
+}
+
+//Series - zero filled points of all buckets from..to, counts are taken from map by bucket unix time
+func Series(g Granularity, from, to time.Time, counts map[int64]uint64) []Point {
+	var res []Point
+
+	for t := g.Bucket(from); !t.After(to); t = t.Add(g.Step) {
+		res = append(res, Point{t, counts[t.Unix()]})
+	}
+
+	return res
+}
+
+//CheckRange - validate series period
+func CheckRange(g Granularity, from, to time.Time) error {
+	if to.Before(from) {
+		return fmt.Errorf("from is after to")
+	}
+
+	if to.Sub(g.Bucket(from))/g.Step >= MaxPoints {
+		return ErrTooManyPoints
+	}
+
+	return nil
+}
+
+//Store - time bucketed visit counters
+type Store interface {
+	Record(e Event) error
+	Series(id uint64, g Granularity, from, to time.Time) ([]Point, error)
+	//UniqueVisitors - approximate number of distinct visitor fingerprints
+	UniqueVisitors(id uint64) (uint64, error)
+	//Breakdown - top values of dimension sorted by count
+	Breakdown(id uint64, dimension string, limit int) ([]Count, error)
+	//Remove - delete all data of link
+	Remove(id uint64) error
+}
@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	ts := time.Date(2020, 10, 4, 17, 18, 35, 0, time.UTC)
+
+	tests := map[string]struct {
+		g      Granularity
+		bucket time.Time
+	}{
+		"Minute": {Minute, time.Date(2020, 10, 4, 17, 18, 0, 0, time.UTC)},
+		"Hour":   {Hour, time.Date(2020, 10, 4, 17, 0, 0, 0, time.UTC)},
+		"Day":    {Day, time.Date(2020, 10, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := tc.g.Bucket(ts); !res.Equal(tc.bucket) {
+				t.Fatalf("Expected %v, got %v", tc.bucket, res)
+			}
+		})
+	}
+}
+
+func TestSeries(t *testing.T) {
+	from := time.Date(2020, 10, 4, 17, 30, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+
+	counts := map[int64]uint64{
+		time.Date(2020, 10, 4, 18, 0, 0, 0, time.UTC).Unix(): 5,
+		time.Date(2020, 10, 4, 21, 0, 0, 0, time.UTC).Unix(): 7,
+	}
+
+	expected := []Point{
+		{time.Date(2020, 10, 4, 17, 0, 0, 0, time.UTC), 0},
+		{time.Date(2020, 10, 4, 18, 0, 0, 0, time.UTC), 5},
+		{time.Date(2020, 10, 4, 19, 0, 0, 0, time.UTC), 0},
+	}
+
+	if res := Series(Hour, from, to, counts); !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
+
+func TestCheckRange(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		g       Granularity
+		from    time.Time
+		isError bool
+	}{
+		"Day of hours":      {Hour, now.Add(-24 * time.Hour), false},
+		"From is after to":  {Hour, now.Add(time.Hour), true},
+		"Too many minutes":  {Minute, now.Add(-48 * time.Hour), true},
+		"Two years of days": {Day, now.AddDate(-2, 0, 0), false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckRange(tc.g, tc.from, now)
+
+			if tc.isError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.isError, err)
+			}
+		})
+	}
+}
+
+func TestParseGranularity(t *testing.T) {
+	if g, err := ParseGranularity("minute"); err != nil || g != Minute {
+		t.Fatalf("Expected %v, got %v %v", Minute, g, err)
+	}
+
+	if _, err := ParseGranularity("week"); err != ErrUnknownGranularity {
+		t.Fatalf("Expected %v, got %v", ErrUnknownGranularity, err)
+	}
+}
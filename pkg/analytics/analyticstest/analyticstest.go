@@ -0,0 +1,67 @@
+package analyticstest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+)
+
+func referrers(t *testing.T, s analytics.Store, id uint64) map[string]uint64 {
+	counts, err := s.Breakdown(id, analytics.DimReferrer, 3*analytics.MaxBreakdown)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := map[string]uint64{}
+	for _, c := range counts {
+		res[c.Name] = c.Count
+	}
+
+	return res
+}
+
+//BreakdownIsBounded - when breakdown is full, new value is kept and the least popular other value is evicted,
+//values with the same count are evicted in name order. Both implementations of Store must pass it
+func BreakdownIsBounded(t *testing.T, s analytics.Store, id uint64) {
+	record := func(domain string) {
+		if err := s.Record(analytics.Event{ID: id, Time: time.Now(), Referrer: "https://" + domain}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2*analytics.MaxBreakdown; i++ {
+		record(fmt.Sprintf("site%03d.com", i))
+		record(fmt.Sprintf("site%03d.com", i))
+	}
+
+	steps := []struct {
+		domain  string
+		kept    string
+		evicted string
+	}{
+		{"new1.com", "new1.com", "site000.com"},
+		{"new2.com", "new2.com", "new1.com"},
+		{"new3.com", "new3.com", "new2.com"},
+	}
+
+	for _, step := range steps {
+		record(step.domain)
+
+		res := referrers(t, s, id)
+
+		if len(res) != 2*analytics.MaxBreakdown {
+			t.Fatalf("%s: expected %v stored values, got %v", step.domain, 2*analytics.MaxBreakdown, len(res))
+		}
+
+		if _, ok := res[step.kept]; !ok {
+			t.Fatalf("%s: expected %v to be kept", step.domain, step.kept)
+		}
+
+		if _, ok := res[step.evicted]; ok {
+			t.Fatalf("%s: expected %v to be evicted", step.domain, step.evicted)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/useragent"
+)
+
+//Breakdown dimensions
+const (
+	DimReferrer = "referrers"
+	DimBrowser  = "browsers"
+	DimOS       = "os"
+	DimDevice   = "devices"
+)
+
+//Dimensions - all breakdown dimensions
+var Dimensions = []string{DimReferrer, DimBrowser, DimOS, DimDevice}
+
+//MaxBreakdown - maximum number of top values in breakdown. Twice more values are stored,
+//so new values have a chance to get into top before they are evicted
+const MaxBreakdown = 100
+
+//Count - number of visits with dimension value
+type Count struct {
+	Name  string `json:"name"`
+	Count uint64 `json:"count"`
+}
+
+//ReferrerDomain - referrer host without www, "(direct)" for empty referrer
+func ReferrerDomain(referrer string) string {
+	if referrer == "" {
+		return "(direct)"
+	}
+
+	u, err := url.Parse(referrer)
+
+	if err != nil || u.Hostname() == "" {
+		return "(unknown)"
+	}
+
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+//Breakdown - dimension values of event
+func (e Event) Breakdown() map[string]string {
+	a := useragent.Parse(e.UserAgent)
+
+	return map[string]string{
+		DimReferrer: ReferrerDomain(e.Referrer),
+		DimBrowser:  a.Browser,
+		DimOS:       a.OS,
+		DimDevice:   a.Device,
+	}
+}
+
+//TopCounts - sort counts by count descending and keep limit of them
+func TopCounts(counts []Count, limit int) []Count {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+
+	if limit < len(counts) {
+		counts = counts[:limit]
+	}
+
+	return counts
+}
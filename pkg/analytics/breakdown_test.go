@@ -0,0 +1,48 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReferrerDomain(t *testing.T) {
+	tests := map[string]struct {
+		referrer string
+		domain   string
+	}{
+		"Direct visit":  {"", "(direct)"},
+		"Search engine": {"https://www.Google.com/search?q=shortener", "google.com"},
+		"Subdomain":     {"https://m.vk.com/feed", "m.vk.com"},
+		"Not URL":       {"android-app", "(unknown)"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := ReferrerDomain(tc.referrer); res != tc.domain {
+				t.Fatalf("Expected %v, got %v", tc.domain, res)
+			}
+		})
+	}
+}
+
+func TestEventBreakdown(t *testing.T) {
+	e := Event{
+		Referrer:  "https://t.co/abc",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 14_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Mobile/15E148 Safari/604.1",
+	}
+
+	expected := map[string]string{DimReferrer: "t.co", DimBrowser: "Safari", DimOS: "iOS", DimDevice: "mobile"}
+
+	if res := e.Breakdown(); !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
+
+func TestTopCounts(t *testing.T) {
+	counts := []Count{{"b", 1}, {"a", 5}, {"c", 1}, {"d", 3}}
+	expected := []Count{{"a", 5}, {"d", 3}, {"b", 1}}
+
+	if res := TopCounts(counts, 3); !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
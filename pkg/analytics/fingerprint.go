@@ -0,0 +1,40 @@
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+//Fingerprinter - privacy preserving visitor id. It is a hash of IP and user agent salted with
+//daily salt, so IP can't be restored and visitors can't be tracked between days
+type Fingerprinter struct {
+	secret []byte
+}
+
+//NewFingerprinter - secret must be shared between instances, random secret is used if it is empty
+func NewFingerprinter(secret string) *Fingerprinter {
+	key := []byte(secret)
+
+	if secret == "" {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+
+	return &Fingerprinter{secret: key}
+}
+
+func sum(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+//Fingerprint - visitor id for day of t
+func (f *Fingerprinter) Fingerprint(ip, userAgent string, t time.Time) string {
+	salt := sum(f.secret, t.UTC().Format("2006-01-02"))
+
+	return hex.EncodeToString(sum(salt, ip+"\n"+userAgent)[:16])
+}
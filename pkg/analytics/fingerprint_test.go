@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	f := NewFingerprinter("secret")
+	day := time.Date(2020, 10, 4, 10, 0, 0, 0, time.UTC)
+	base := f.Fingerprint("1.1.1.1", "curl", day)
+
+	tests := map[string]struct {
+		f     *Fingerprinter
+		ip    string
+		ua    string
+		t     time.Time
+		equal bool
+	}{
+		"Same day":         {f, "1.1.1.1", "curl", day.Add(13 * time.Hour), true},
+		"Next day":         {f, "1.1.1.1", "curl", day.Add(14 * time.Hour), false},
+		"Other IP":         {f, "1.1.1.2", "curl", day, false},
+		"Other user agent": {f, "1.1.1.1", "wget", day, false},
+		"Other secret":     {NewFingerprinter("other"), "1.1.1.1", "curl", day, false},
+		"Same secret":      {NewFingerprinter("secret"), "1.1.1.1", "curl", day, true},
+		"Random secret":    {NewFingerprinter(""), "1.1.1.1", "curl", day, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := tc.f.Fingerprint(tc.ip, tc.ua, tc.t)
+
+			if (res == base) != tc.equal {
+				t.Fatalf("Expected equal %v, got %v and %v", tc.equal, base, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package hll
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+//precision - 2^12 registers, standard error is about 1.6%
+const (
+	precision = 12
+	registers = 1 << precision
+)
+
+//Sketch - HyperLogLog approximate distinct counter
+type Sketch struct {
+	registers [registers]uint8
+}
+
+//New ...
+func New() *Sketch {
+	return &Sketch{}
+}
+
+//hash - FNV-1a with murmur3 finalizer for better distribution of high bits
+func hash(value string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	x := h.Sum64()
+
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return x
+}
+
+//Add ...
+func (s *Sketch) Add(value string) {
+	x := hash(value)
+	index := x >> (64 - precision)
+	rank := uint8(bits.LeadingZeros64(x<<precision|1<<(precision-1)) + 1)
+
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+//Count - estimated number of distinct values
+func (s *Sketch) Count() uint64 {
+	sum := 0.0
+	zeros := 0
+
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	m := float64(registers)
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+
+	// linear counting is more precise for small cardinalities
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
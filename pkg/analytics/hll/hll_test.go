@@ -0,0 +1,37 @@
+package hll
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	tests := map[string]struct {
+		distinct int
+		repeats  int
+	}{
+		"Empty":        {0, 1},
+		"Small":        {10, 3},
+		"Thousand":     {1000, 2},
+		"Hundred kilo": {100000, 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := New()
+
+			for r := 0; r < tc.repeats; r++ {
+				for i := 0; i < tc.distinct; i++ {
+					s.Add("visitor-" + strconv.Itoa(i))
+				}
+			}
+
+			count := float64(s.Count())
+			expected := float64(tc.distinct)
+
+			if count < expected*0.95 || count > expected*1.05 {
+				t.Fatalf("Expected about %v, got %v", expected, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics/hll"
+)
+
+type key struct {
+	id   uint64
+	name string
+}
+
+//Store - in-memory analytics for single instance and tests
+type Store struct {
+	mu       sync.Mutex
+	buckets  map[key]map[int64]uint64
+	visitors map[uint64]*hll.Sketch
+	counts   map[key]map[string]uint64
+}
+
+//New ...
+func New() *Store {
+	return &Store{
+		buckets:  map[key]map[int64]uint64{},
+		visitors: map[uint64]*hll.Sketch{},
+		counts:   map[key]map[string]uint64{},
+	}
+}
+
+//Record ...
+func (s *Store) Record(e analytics.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Visitor != "" {
+		if s.visitors[e.ID] == nil {
+			s.visitors[e.ID] = hll.New()
+		}
+		s.visitors[e.ID].Add(e.Visitor)
+	}
+
+	for dim, value := range e.Breakdown() {
+		s.count(key{e.ID, dim}, value)
+	}
+
+	for _, g := range analytics.Granularities {
+		k := key{e.ID, g.Name}
+
+		if s.buckets[k] == nil {
+			s.buckets[k] = map[int64]uint64{}
+		}
+
+		buckets := s.buckets[k]
+		buckets[g.Bucket(e.Time).Unix()]++
+
+		expired := e.Time.Add(-g.Retention).Unix()
+		for b := range buckets {
+			if b < expired {
+				delete(buckets, b)
+			}
+		}
+	}
+
+	return nil
+}
+
+//Series ...
+func (s *Store) Series(id uint64, g analytics.Granularity, from, to time.Time) ([]analytics.Point, error) {
+	if err := analytics.CheckRange(g, from, to); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return analytics.Series(g, from, to, s.buckets[key{id, g.Name}]), nil
+}
+
+//UniqueVisitors ...
+func (s *Store) UniqueVisitors(id uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sketch, ok := s.visitors[id]; ok {
+		return sketch.Count(), nil
+	}
+
+	return 0, nil
+}
+
+//count - increment value counter and evict the least popular value except the incremented one,
+//values with the same count are evicted in name order like in Redis sorted set
+func (s *Store) count(k key, value string) {
+	counts := s.counts[k]
+
+	if counts == nil {
+		counts = map[string]uint64{}
+		s.counts[k] = counts
+	}
+
+	counts[value]++
+
+	if len(counts) <= 2*analytics.MaxBreakdown {
+		return
+	}
+
+	min := ""
+	for name, c := range counts {
+		if name != value && (min == "" || c < counts[min] || c == counts[min] && name < min) {
+			min = name
+		}
+	}
+	delete(counts, min)
+}
+
+//Remove ...
+func (s *Store) Remove(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.visitors, id)
+
+	for k := range s.buckets {
+		if k.id == id {
+			delete(s.buckets, k)
+		}
+	}
+
+	for k := range s.counts {
+		if k.id == id {
+			delete(s.counts, k)
+		}
+	}
+
+	return nil
+}
+
+//Breakdown ...
+func (s *Store) Breakdown(id uint64, dimension string, limit int) ([]analytics.Count, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := []analytics.Count{}
+
+	for name, c := range s.counts[key{id, dimension}] {
+		res = append(res, analytics.Count{Name: name, Count: c})
+	}
+
+	return analytics.TopCounts(res, limit), nil
+}
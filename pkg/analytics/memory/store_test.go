@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics/analyticstest"
+)
+
+func TestStore(t *testing.T) {
+	s := New()
+	now := time.Date(2020, 10, 4, 17, 18, 35, 0, time.UTC)
+
+	for _, d := range []time.Duration{0, 10 * time.Second, time.Minute, time.Hour} {
+		s.Record(analytics.Event{ID: 1, Time: now.Add(d)})
+	}
+	s.Record(analytics.Event{ID: 2, Time: now})
+
+	tests := map[string]struct {
+		g      analytics.Granularity
+		to     time.Time
+		visits []uint64
+	}{
+		"Minutes": {analytics.Minute, now.Add(2 * time.Minute), []uint64{2, 1, 0}},
+		"Hours":   {analytics.Hour, now.Add(time.Hour), []uint64{3, 1}},
+		"Days":    {analytics.Day, now.Add(time.Hour), []uint64{4}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			points, err := s.Series(1, tc.g, now, tc.to)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var visits []uint64
+			for _, p := range points {
+				visits = append(visits, p.Visits)
+			}
+
+			if len(visits) != len(tc.visits) {
+				t.Fatalf("Expected %v, got %v", tc.visits, visits)
+			}
+
+			for i := range visits {
+				if visits[i] != tc.visits[i] {
+					t.Fatalf("Expected %v, got %v", tc.visits, visits)
+				}
+			}
+		})
+	}
+}
+
+func TestUniqueVisitors(t *testing.T) {
+	s := New()
+
+	for _, v := range []string{"a", "b", "a", ""} {
+		s.Record(analytics.Event{ID: 1, Time: time.Now(), Visitor: v})
+	}
+
+	tests := map[string]struct {
+		id     uint64
+		unique uint64
+	}{
+		"Visited link":     {1, 2},
+		"Not visited link": {2, 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := s.UniqueVisitors(tc.id)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res != tc.unique {
+				t.Fatalf("Expected %v, got %v", tc.unique, res)
+			}
+		})
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	s := New()
+
+	for _, ref := range []string{"https://google.com", "https://t.co/x", "https://google.com/q", ""} {
+		s.Record(analytics.Event{ID: 1, Time: time.Now(), Referrer: ref, UserAgent: "curl/7.68.0"})
+	}
+
+	tests := map[string]struct {
+		dim    string
+		limit  int
+		counts []analytics.Count
+	}{
+		"Referrers":   {analytics.DimReferrer, 2, []analytics.Count{{Name: "google.com", Count: 2}, {Name: "(direct)", Count: 1}}},
+		"Devices":     {analytics.DimDevice, 10, []analytics.Count{{Name: "bot", Count: 4}}},
+		"Unknown dim": {"colors", 10, []analytics.Count{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := s.Breakdown(1, tc.dim, tc.limit)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tc.counts) {
+				t.Fatalf("Expected %v, got %v", tc.counts, res)
+			}
+		})
+	}
+}
+
+func TestBreakdownIsBounded(t *testing.T) {
+	s := New()
+
+	for i := 0; i < 3*analytics.MaxBreakdown; i++ {
+		s.Record(analytics.Event{ID: 1, Time: time.Now(), Referrer: "https://site" + strconv.Itoa(i) + ".com"})
+	}
+
+	if n := len(s.counts[key{1, analytics.DimReferrer}]); n != 2*analytics.MaxBreakdown {
+		t.Fatalf("Expected %v stored values, got %v", 2*analytics.MaxBreakdown, n)
+	}
+}
+
+func TestBreakdownEviction(t *testing.T) {
+	analyticstest.BreakdownIsBounded(t, New(), 2)
+}
+
+func TestRemove(t *testing.T) {
+	s := New()
+	now := time.Now()
+
+	s.Record(analytics.Event{ID: 1, Time: now, Referrer: "https://vk.com", Visitor: "v1"})
+	s.Record(analytics.Event{ID: 2, Time: now, Visitor: "v1"})
+
+	if err := s.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if uv, _ := s.UniqueVisitors(1); uv != 0 || len(s.buckets) != len(analytics.Granularities) || len(s.counts) != len(analytics.Dimensions) {
+		t.Fatalf("Expected only data of link 2, got %v %v %v", uv, s.buckets, s.counts)
+	}
+
+	if uv, _ := s.UniqueVisitors(2); uv != 1 {
+		t.Fatalf("Expected 1 visitor of link 2, got %v", uv)
+	}
+}
@@ -0,0 +1,187 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/gomodule/redigo/redis"
+)
+
+// KEYS[1] - breakdown, ARGV[1] - value, ARGV[2] - max size, ARGV[3] - ttl in seconds.
+// Like memory store, the least popular value except the incremented one is evicted, ties are removed in name order
+var countScript = redis.NewScript(1, `
+redis.call('ZINCRBY', KEYS[1], 1, ARGV[1])
+if redis.call('ZCARD', KEYS[1]) > tonumber(ARGV[2]) then
+	local low = redis.call('ZRANGE', KEYS[1], 0, 1)
+	if low[1] == ARGV[1] then
+		redis.call('ZREM', KEYS[1], low[2])
+	else
+		redis.call('ZREM', KEYS[1], low[1])
+	end
+end
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+`)
+
+//Store - analytics in Redis. Buckets of one shard are fields of hash
+//stats:{id}:{granularity}:{shard start}, the hash expires after retention period.
+//Visitor fingerprints are counted in HyperLogLog uv:{id}, breakdowns are kept in sorted sets bd:{id}:{dimension}
+type Store struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Store {
+	return &Store{pool: pool}
+}
+
+func shardKey(id uint64, g analytics.Granularity, shard time.Time) string {
+	return fmt.Sprintf("stats:%d:%s:%d", id, g.Name, shard.Unix())
+}
+
+func visitorsKey(id uint64) string {
+	return fmt.Sprintf("uv:%d", id)
+}
+
+func breakdownKey(id uint64, dimension string) string {
+	return fmt.Sprintf("bd:%d:%s", id, dimension)
+}
+
+//Record ...
+func (s *Store) Record(e analytics.Event) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+
+	for _, g := range analytics.Granularities {
+		shard := g.ShardOf(e.Time)
+		key := shardKey(e.ID, g, shard)
+
+		conn.Send("HINCRBY", key, g.Bucket(e.Time).Unix(), 1)
+		conn.Send("EXPIREAT", key, shard.Add(g.Shard+g.Retention).Unix())
+	}
+
+	for dim, value := range e.Breakdown() {
+		key := breakdownKey(e.ID, dim)
+
+		// keep twice more values than reported top
+		countScript.Send(conn, key, value, 2*analytics.MaxBreakdown, int64(analytics.Day.Retention/time.Second))
+	}
+
+	if e.Visitor != "" {
+		conn.Send("PFADD", visitorsKey(e.ID), e.Visitor)
+		conn.Send("EXPIRE", visitorsKey(e.ID), int64(analytics.Day.Retention/time.Second))
+	}
+
+	_, err := conn.Do("EXEC")
+
+	return err
+}
+
+//Series ...
+func (s *Store) Series(id uint64, g analytics.Granularity, from, to time.Time) ([]analytics.Point, error) {
+	if err := analytics.CheckRange(g, from, to); err != nil {
+		return nil, err
+	}
+
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	shards := 0
+
+	for shard := g.ShardOf(from); !shard.After(to); shard = shard.Add(g.Shard) {
+		conn.Send("HGETALL", shardKey(id, g, shard))
+		shards++
+	}
+
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	counts := map[int64]uint64{}
+
+	for i := 0; i < shards; i++ {
+		values, err := redis.StringMap(conn.Receive())
+
+		if err != nil {
+			return nil, err
+		}
+
+		for bucket, count := range values {
+			b, err := strconv.ParseInt(bucket, 10, 64)
+
+			if err != nil {
+				continue
+			}
+
+			c, err := strconv.ParseUint(count, 10, 64)
+
+			if err != nil {
+				return nil, err
+			}
+
+			counts[b] = c
+		}
+	}
+
+	return analytics.Series(g, from, to, counts), nil
+}
+
+//UniqueVisitors ...
+func (s *Store) UniqueVisitors(id uint64) (uint64, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	return redis.Uint64(conn.Do("PFCOUNT", visitorsKey(id)))
+}
+
+//Remove - shards which are not expired yet are computed from retention periods, so keys are not scanned
+func (s *Store) Remove(id uint64) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	now := time.Now()
+	keys := []interface{}{visitorsKey(id)}
+
+	for _, dim := range analytics.Dimensions {
+		keys = append(keys, breakdownKey(id, dim))
+	}
+
+	for _, g := range analytics.Granularities {
+		for shard := g.ShardOf(now.Add(-g.Shard - g.Retention)); !shard.After(now); shard = shard.Add(g.Shard) {
+			keys = append(keys, shardKey(id, g, shard))
+		}
+	}
+
+	_, err := conn.Do("DEL", keys...)
+
+	return err
+}
+
+//Breakdown ...
+func (s *Store) Breakdown(id uint64, dimension string, limit int) ([]analytics.Count, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	values, err := redis.Strings(conn.Do("ZREVRANGE", breakdownKey(id, dimension), 0, limit-1, "WITHSCORES"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]analytics.Count, 0, len(values)/2)
+
+	for i := 0; i+1 < len(values); i += 2 {
+		c, err := strconv.ParseUint(values[i+1], 10, 64)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, analytics.Count{Name: values[i], Count: c})
+	}
+
+	return analytics.TopCounts(res, limit), nil
+}
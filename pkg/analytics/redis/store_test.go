@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics/analyticstest"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestStore() *Store {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func removeStats(s *Store, id uint64) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	keys, _ := redis.Values(conn.Do("KEYS", fmt.Sprintf("stats:%d:*", id)))
+	keys = append(keys, visitorsKey(id))
+	for _, dim := range analytics.Dimensions {
+		keys = append(keys, breakdownKey(id, dim))
+	}
+	conn.Do("DEL", keys...)
+}
+
+func TestStoreRedis(t *testing.T) {
+	s := NewTestStore()
+	defer removeStats(s, 1000)
+
+	now := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+
+	for _, d := range []time.Duration{0, 10 * time.Second, time.Minute, time.Hour} {
+		if err := s.Record(analytics.Event{ID: 1000, Time: now.Add(d)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	points, err := s.Series(1000, analytics.Hour, now.Add(-time.Hour), now.Add(time.Hour))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []analytics.Point{
+		{Time: now.Add(-time.Hour), Visits: 0},
+		{Time: now, Visits: 3},
+		{Time: now.Add(time.Hour), Visits: 1},
+	}
+
+	if !reflect.DeepEqual(points, expected) {
+		t.Fatalf("Expected %v, got %v", expected, points)
+	}
+
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	ttl, _ := redis.Int64(conn.Do("TTL", shardKey(1000, analytics.Minute, analytics.Minute.ShardOf(now))))
+
+	if ttl <= 0 {
+		t.Fatalf("Expected shard with TTL, got %v", ttl)
+	}
+}
+
+func TestUniqueVisitorsRedis(t *testing.T) {
+	s := NewTestStore()
+	defer removeStats(s, 1001)
+
+	for _, v := range []string{"a", "b", "a", ""} {
+		if err := s.Record(analytics.Event{ID: 1001, Time: time.Now(), Visitor: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := s.UniqueVisitors(1001)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res != 2 {
+		t.Fatalf("Expected %v, got %v", 2, res)
+	}
+}
+
+func TestBreakdownRedis(t *testing.T) {
+	s := NewTestStore()
+	defer removeStats(s, 1002)
+
+	for _, ref := range []string{"https://google.com", "https://t.co/x", "https://google.com/q", ""} {
+		if err := s.Record(analytics.Event{ID: 1002, Time: time.Now(), Referrer: ref}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := s.Breakdown(1002, analytics.DimReferrer, 2)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []analytics.Count{{Name: "google.com", Count: 2}, {Name: "t.co", Count: 1}}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
+
+func TestBreakdownEvictionRedis(t *testing.T) {
+	s := NewTestStore()
+	defer removeStats(s, 1004)
+
+	analyticstest.BreakdownIsBounded(t, s, 1004)
+}
+
+func TestRemoveRedis(t *testing.T) {
+	s := NewTestStore()
+	defer removeStats(s, 1003)
+
+	now := time.Now().UTC()
+
+	for _, d := range []time.Duration{0, -25 * time.Hour, -40 * 24 * time.Hour} {
+		e := analytics.Event{ID: 1003, Time: now.Add(d), Referrer: "https://vk.com", UserAgent: "Mozilla/5.0 (Windows NT 10.0) Chrome/86.0", Visitor: "v1"}
+
+		if err := s.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Remove(1003); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	keys, err := redis.Strings(conn.Do("KEYS", "*:1003:*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := redis.Bool(conn.Do("EXISTS", visitorsKey(1003))); exists || len(keys) != 0 {
+		t.Fatalf("Expected all keys to be removed, got %v %v", keys, exists)
+	}
+}
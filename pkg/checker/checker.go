@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+)
+
+//Match - threat list entry matched by URL
+type Match struct {
+	Threat     string
+	Expression string
+}
+
+//URLChecker - checks URL against list of malicious URLs. Returns nil Match for safe URL
+type URLChecker interface {
+	Check(rawURL string) (*Match, error)
+}
+
+//DisabledReason - reason stored in disabled item
+func (m *Match) DisabledReason() string {
+	return fmt.Sprintf("threat: %s", m.Threat)
+}
+
+//Recheck - check all stored links and disable flagged ones, onDisable is called for every disabled link.
+//Links paused by owner are checked too, their reason is replaced, so owner can't enable them.
+//Errors of single links are passed to onError and don't stop the sweep. Returns number of disabled links
+func Recheck(c URLChecker, db store.Storage, onDisable func(item *store.Item, reason string), onError func(error)) (int, error) {
+	disabled := 0
+
+	err := db.Walk(func(item *store.Item) error {
+		if item.Disabled && item.DisabledReason != store.PausedReason {
+			return nil
+		}
+
+		m, err := c.Check(item.URL)
+
+		if err != nil {
+			onError(fmt.Errorf("check %d: %v", item.ID, err))
+			return nil
+		}
+
+		if m == nil {
+			return nil
+		}
+
+		err = db.Disable(item.ID, m.DisabledReason())
+
+		if err == store.ErrItemNotFound {
+			return nil
+		} else if err != nil {
+			onError(fmt.Errorf("disable %d: %v", item.ID, err))
+			return nil
+		}
+
+		disabled++
+		onDisable(item, m.DisabledReason())
+
+		return nil
+	})
+
+	return disabled, err
+}
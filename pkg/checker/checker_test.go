@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/VladimirStepanov/urlshortener/pkg/store/teststore"
+)
+
+type testChecker map[string]string
+
+func (c testChecker) Check(rawURL string) (*Match, error) {
+	if rawURL == "https://flaky.com" {
+		return nil, errors.New("lookup timeout")
+	}
+	if threat, ok := c[rawURL]; ok {
+		return &Match{Threat: threat, Expression: rawURL}, nil
+	}
+	return nil, nil
+}
+
+func TestRecheck(t *testing.T) {
+	db := teststore.New(map[uint64]*store.Item{
+		1: {ID: 1, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: "10.1.2380 1:0:0"}},
+		2: {ID: 2, BaseItem: store.BaseItem{URL: "https://evil.com", Expire: "10.1.2380 1:0:0"}},
+		3: {ID: 3, BaseItem: store.BaseItem{URL: "https://evil.com", Expire: "10.1.2380 1:0:0", Disabled: true, DisabledReason: "abuse"}},
+		4: {ID: 4, BaseItem: store.BaseItem{URL: "https://flaky.com", Expire: "10.1.2380 1:0:0"}},
+		5: {ID: 5, BaseItem: store.BaseItem{URL: "https://phish.com", Expire: "10.1.2380 1:0:0"}},
+		6: {ID: 6, BaseItem: store.BaseItem{URL: "https://phish.com", Expire: "10.1.2380 1:0:0", Disabled: true, DisabledReason: store.PausedReason}},
+	})
+
+	notified := map[uint64]string{}
+	var errs []error
+
+	disabled, err := Recheck(testChecker{"https://evil.com": "malware", "https://phish.com": "phishing"}, db,
+		func(item *store.Item, reason string) { notified[item.ID] = reason },
+		func(err error) { errs = append(errs, err) },
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if disabled != 3 {
+		t.Fatalf("Expected 3 disabled links, got %d", disabled)
+	}
+
+	if len(notified) != 3 || notified[2] != "threat: malware" || notified[5] != "threat: phishing" || notified[6] != "threat: phishing" {
+		t.Fatalf("Expected disabled links to be reported, got %v", notified)
+	}
+
+	//error of one link doesn't stop the sweep
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+
+	tests := map[string]struct {
+		id       uint64
+		disabled bool
+		reason   string
+	}{
+		"Safe link":             {1, false, ""},
+		"Malicious link":        {2, true, "threat: malware"},
+		"Already disabled link": {3, true, "abuse"},
+		"Link with check error": {4, false, ""},
+		"Other malicious link":  {5, true, "threat: phishing"},
+		"Paused malicious link": {6, true, "threat: phishing"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			item, _ := db.Load(tc.id)
+
+			if item.Disabled != tc.disabled || item.DisabledReason != tc.reason {
+				t.Fatalf("Expected %v %q, got %v %q", tc.disabled, tc.reason, item.Disabled, item.DisabledReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package hashlist
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+)
+
+//Expressions - host suffix / path prefix combinations of URL in Safe Browsing style.
+//Up to 5 hosts (exact host and 4 suffixes) and up to 6 paths (exact path with and without query and 4 prefixes)
+func Expressions(rawURL string) ([]string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+
+	if err != nil {
+		return nil, err
+	}
+
+	//stored links keep host as it was submitted, so internationalized host is converted to punycode here
+	host, err := urlnorm.Domain(strings.Trim(u.Hostname(), "."))
+
+	if err != nil {
+		return nil, err
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	var res []string
+
+	for _, h := range hostSuffixes(host) {
+		for _, p := range pathPrefixes(path, u.RawQuery) {
+			res = append(res, h+p)
+		}
+	}
+
+	return res, nil
+}
+
+func hostSuffixes(host string) []string {
+	res := []string{host}
+
+	if net.ParseIP(host) != nil {
+		return res
+	}
+
+	parts := strings.Split(host, ".")
+
+	start := len(parts) - 5
+	if start < 1 {
+		start = 1
+	}
+
+	for i := start; i < len(parts)-1; i++ {
+		res = append(res, strings.Join(parts[i:], "."))
+	}
+
+	return res
+}
+
+func pathPrefixes(path, query string) []string {
+	var res []string
+
+	if query != "" {
+		res = append(res, path+"?"+query)
+	}
+	res = append(res, path)
+
+	prefix := "/"
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := 0; i < len(segments) && i < 4; i++ {
+		if prefix != path {
+			res = append(res, prefix)
+		}
+		prefix += segments[i] + "/"
+	}
+
+	return res
+}
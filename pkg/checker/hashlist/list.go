@@ -0,0 +1,153 @@
+package hashlist
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/checker"
+)
+
+//DefaultThreat - threat type for entries without explicit type
+const DefaultThreat = "malware"
+
+//List - local list of SHA256 hash prefixes of malicious URL expressions.
+//Every line of list file is "[threat:]hex_prefix", prefix length is from 4 to 32 bytes.
+//Prefix matches are not confirmed by full hash lookup, so list must contain long enough prefixes
+type List struct {
+	mu       sync.RWMutex
+	file     string
+	modTime  time.Time
+	prefixes map[int]map[string]string
+}
+
+//New - create empty list which is filled from file on Reload
+func New(file string) *List {
+	return &List{file: file, prefixes: map[int]map[string]string{}}
+}
+
+//Load - create list and load it from file
+func Load(file string) (*List, error) {
+	l := New(file)
+
+	if _, err := l.Reload(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func parse(file string) (map[int]map[string]string, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	res := map[int]map[string]string{}
+	scanner := bufio.NewScanner(f)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		threat := DefaultThreat
+		if i := strings.IndexByte(line, ':'); i != -1 {
+			threat, line = line[:i], line[i+1:]
+		}
+
+		prefix, err := hex.DecodeString(line)
+
+		if err != nil || len(prefix) < 4 || len(prefix) > sha256.Size {
+			return nil, fmt.Errorf("%s:%d: invalid hash prefix", file, n)
+		}
+
+		if res[len(prefix)] == nil {
+			res[len(prefix)] = map[string]string{}
+		}
+		res[len(prefix)][string(prefix)] = threat
+	}
+
+	return res, scanner.Err()
+}
+
+//Reload - load list file if it is changed since last load
+func (l *List) Reload() (bool, error) {
+	st, err := os.Stat(l.file)
+
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.RLock()
+	changed := !st.ModTime().Equal(l.modTime)
+	l.mu.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+
+	prefixes, err := parse(l.file)
+
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.Lock()
+	l.prefixes = prefixes
+	l.modTime = st.ModTime()
+	l.mu.Unlock()
+
+	return true, nil
+}
+
+//Watch - reload list every interval until stop is closed
+func (l *List) Watch(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := l.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+//Check ...
+func (l *List) Check(rawURL string) (*checker.Match, error) {
+	expressions, err := Expressions(rawURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, e := range expressions {
+		sum := sha256.Sum256([]byte(e))
+
+		for n, prefixes := range l.prefixes {
+			if threat, ok := prefixes[string(sum[:n])]; ok {
+				return &checker.Match{Threat: threat, Expression: e}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
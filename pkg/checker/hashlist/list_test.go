@@ -0,0 +1,154 @@
+package hashlist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func prefix(expression string, n int) string {
+	sum := sha256.Sum256([]byte(expression))
+	return hex.EncodeToString(sum[:n])
+}
+
+func writeList(t *testing.T, file, data string) {
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpressions(t *testing.T) {
+	tests := map[string]struct {
+		url         string
+		expressions []string
+	}{
+		"Host and path with query": {
+			"http://a.b.c/1/2.html?param=1",
+			[]string{
+				"a.b.c/1/2.html?param=1", "a.b.c/1/2.html", "a.b.c/", "a.b.c/1/",
+				"b.c/1/2.html?param=1", "b.c/1/2.html", "b.c/", "b.c/1/",
+			},
+		},
+		"Long host": {
+			"http://a.b.c.d.e.f.g/",
+			[]string{"a.b.c.d.e.f.g/", "c.d.e.f.g/", "d.e.f.g/", "e.f.g/", "f.g/"},
+		},
+		"IP address": {
+			"http://1.2.3.4/1/",
+			[]string{"1.2.3.4/1/", "1.2.3.4/"},
+		},
+		"Upper case host without path": {
+			"https://WWW.Evil.COM.",
+			[]string{"www.evil.com/", "evil.com/"},
+		},
+		"Internationalized host": {
+			"https://Пример.рф/",
+			[]string{"xn--e1afmkfd.xn--p1ai/"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := Expressions(tc.url)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tc.expressions) {
+				t.Fatalf("Expected %v, got %v", tc.expressions, res)
+			}
+		})
+	}
+}
+
+func TestListCheck(t *testing.T) {
+	file, err := ioutil.TempFile("", "threats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	writeList(t, file.Name(), "# test list\n"+prefix("evil.com/", 4)+"\nphishing:"+prefix("bank.example/login/", 32)+"\n")
+
+	l, err := Load(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		url    string
+		threat string
+	}{
+		"Safe URL":                {"https://vk.com/feed", ""},
+		"Malicious domain":        {"https://www.evil.com/some/page", "malware"},
+		"Phishing path prefix":    {"https://bank.example/login/form?x=1", "phishing"},
+		"Not matched other path":  {"https://bank.example/about", ""},
+		"Not matched parent host": {"https://example/login/", ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := l.Check(tc.url)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.threat == "" && m != nil {
+				t.Fatalf("Expected nil, got %v", m)
+			}
+
+			if tc.threat != "" && (m == nil || m.Threat != tc.threat) {
+				t.Fatalf("Expected %v, got %v", tc.threat, m)
+			}
+		})
+	}
+}
+
+func TestListReload(t *testing.T) {
+	file, err := ioutil.TempFile("", "threats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	writeList(t, file.Name(), "")
+
+	l, err := Load(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, _ := l.Reload(); changed {
+		t.Fatalf("Expected unchanged list")
+	}
+
+	writeList(t, file.Name(), prefix("evil.com/", 8)+"\n")
+	os.Chtimes(file.Name(), time.Now(), time.Now().Add(time.Minute))
+
+	if changed, err := l.Reload(); !changed || err != nil {
+		t.Fatalf("Expected reloaded list, got %v %v", changed, err)
+	}
+
+	if m, _ := l.Check("http://evil.com"); m == nil {
+		t.Fatalf("Expected match after reload")
+	}
+
+	writeList(t, file.Name(), "not_hex\n")
+	os.Chtimes(file.Name(), time.Now(), time.Now().Add(2*time.Minute))
+
+	if _, err := l.Reload(); err == nil {
+		t.Fatalf("Expected error for invalid list")
+	}
+
+	if m, _ := l.Check("http://evil.com"); m == nil {
+		t.Fatalf("Previous list must be kept after failed reload")
+	}
+}
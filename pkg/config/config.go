@@ -1,6 +1,9 @@
 package config
 
 import (
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
 )
@@ -12,6 +15,79 @@ type Config struct {
 	RedisHost string `env:"REDIS_HOST"`
 	RedisPort string `env:"REDIS_PORT"`
 	LogLevel  string `env:"LOG_LEVEL"`
+	//AdminToken - bearer token for /admin endpoints, admin API is disabled if it is empty
+	AdminToken string `env:"ADMIN_TOKEN"`
+
+	//RateLimitBackend - "memory" for single instance or "redis" for shared limits
+	RateLimitBackend  string          `env:"RATE_LIMIT_BACKEND" envDefault:"memory"`
+	RateLimitEncode   ratelimit.Limit `env:"RATE_LIMIT_ENCODE" envDefault:"30/1m"`
+	RateLimitRedirect ratelimit.Limit `env:"RATE_LIMIT_REDIRECT" envDefault:"300/1m"`
+	RateLimitAPI      ratelimit.Limit `env:"RATE_LIMIT_API" envDefault:"120/1m"`
+	//APIKeys - clients with these X-API-Key values get their own limits, other clients are limited by IP
+	APIKeys []string `env:"API_KEYS" envSeparator:","`
+
+	AllowedSchemes       []string `env:"ALLOWED_SCHEMES" envSeparator:"," envDefault:"http,https"`
+	BlocklistFile        string   `env:"BLOCKLIST_FILE"`
+	AllowPrivateNetworks bool     `env:"ALLOW_PRIVATE_NETWORKS"`
+	//SelfHosts - hostnames of the shortener, links to them are rejected
+	SelfHosts []string `env:"SELF_HOSTS" envSeparator:","`
+
+	//ThreatListFile - hash prefix list of malicious URLs, checking is disabled if it is empty
+	ThreatListFile   string        `env:"THREAT_LIST_FILE"`
+	ThreatListReload time.Duration `env:"THREAT_LIST_RELOAD" envDefault:"1m"`
+	RecheckInterval  time.Duration `env:"RECHECK_INTERVAL" envDefault:"1h"`
+
+	//VisitorSecret - secret for daily salts of visitor fingerprints, must be the same on all instances
+	VisitorSecret string `env:"VISITOR_SECRET"`
+
+	WebhookTimeout     time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	WebhookMaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	//EventQueueSize - link events waiting for publishers, new events are dropped when queue is full
+	EventQueueSize int `env:"EVENT_QUEUE_SIZE" envDefault:"10000"`
+
+	//EventStream - Redis Stream of link events, publishing is disabled if it is empty
+	EventStream       string `env:"EVENT_STREAM" envDefault:"links:events"`
+	EventStreamMaxLen int    `env:"EVENT_STREAM_MAXLEN" envDefault:"1000000"`
+
+	AlertInterval time.Duration `env:"ALERT_INTERVAL" envDefault:"1m"`
+	//SMTPAddr - host:port of SMTP server for email alerts, email alerts are disabled if it is empty
+	SMTPAddr     string `env:"SMTP_ADDR"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+	SMTPUser     string `env:"SMTP_USER"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+
+	//Codec - codec of new codes: base62, base58, base36 or custom with CodecAlphabet, codes are padded to CodecMinLength
+	Codec          string `env:"CODEC" envDefault:"base62"`
+	CodecAlphabet  string `env:"CODEC_ALPHABET"`
+	CodecMinLength int    `env:"CODEC_MIN_LENGTH" envDefault:"0"`
+	//CodecCheck - append check character to new codes
+	CodecCheck bool `env:"CODEC_CHECK"`
+	//DecodeCodecs - codecs of existing codes, they stay decodable after Codec is changed
+	DecodeCodecs []string `env:"DECODE_CODECS" envSeparator:"," envDefault:"base62"`
+	//CodecKeys - secret keys of id permutation, the first one is used for new codes and others only for decoding.
+	//Ids are not permuted if it is empty
+	CodecKeys []string `env:"CODEC_KEYS" envSeparator:","`
+	//DenyWords - new links don't get codes containing these words, codes equal to routes are always skipped
+	DenyWords     []string `env:"DENY_WORDS" envSeparator:","`
+	DenyWordsFile string   `env:"DENY_WORDS_FILE"`
+
+	//DedupURLs - return existing link with the same normalized URL and options instead of creating new one
+	DedupURLs bool `env:"DEDUP_URLS"`
+	//StripTrackingParams - remove TrackingParams from query of new links, name ended with * is prefix
+	StripTrackingParams bool     `env:"STRIP_TRACKING_PARAMS"`
+	TrackingParams      []string `env:"TRACKING_PARAMS" envSeparator:"," envDefault:"utm_*,fbclid,gclid"`
+
+	//IDAllocator - ids of new links: random, counter, block (ranges of IDBlockSize) or snowflake with NodeID
+	IDAllocator string `env:"ID_ALLOCATOR" envDefault:"random"`
+	IDBlockSize uint64 `env:"ID_BLOCK_SIZE" envDefault:"1000"`
+	NodeID      uint64 `env:"NODE_ID"`
+	//KeyPoolLow - refill pool of pre-generated ids up to KeyPoolSize when it has less ids, pool is disabled if it is 0
+	KeyPoolLow      int           `env:"KEY_POOL_LOW"`
+	KeyPoolSize     int           `env:"KEY_POOL_SIZE" envDefault:"10000"`
+	KeyPoolInterval time.Duration `env:"KEY_POOL_INTERVAL" envDefault:"1s"`
+
+	//ClickIDParam - query parameter with click id added to destination URL, conversion tracking is disabled if it is empty
+	ClickIDParam string `env:"CLICK_ID_PARAM"`
 }
 
 //New ...
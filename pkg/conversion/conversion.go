@@ -0,0 +1,53 @@
+package conversion
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	//Window - attribution window, conversion after it is not counted
+	Window = 30 * 24 * time.Hour
+	//Retention - conversions are kept as long as daily visit buckets
+	Retention = 2 * 365 * 24 * time.Hour
+)
+
+//ErrUnknownClick - click is unknown, expired, already converted or belongs to other link
+var ErrUnknownClick = fmt.Errorf("unknown click")
+
+//Store - clicks waiting for conversion and conversions of links
+type Store interface {
+	//Click - remember click of link at t for attribution window
+	Click(id string, linkID uint64, t time.Time) error
+	//Convert - count conversion of link at t if click belongs to it, every click is converted once
+	Convert(id string, linkID uint64, t time.Time) error
+	//Count - conversions of link from..to
+	Count(linkID uint64, from, to time.Time) (uint64, error)
+	//Remove - delete conversions of link, clicks which are not converted expire after Window
+	Remove(linkID uint64) error
+}
+
+//AppendClickID - add click id to query of destination URL, URL which can't be parsed is returned as is
+func AppendClickID(rawURL, param, id string) string {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return rawURL
+	}
+
+	q := u.Query()
+	q.Set(param, id)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+//Rate - part of visits which converted, 0 without visits
+func Rate(conversions, visits uint64) float64 {
+	if visits == 0 {
+		return 0
+	}
+
+	return float64(conversions) / float64(visits)
+}
@@ -0,0 +1,43 @@
+package conversion
+
+import "testing"
+
+func TestAppendClickID(t *testing.T) {
+	tests := map[string]struct {
+		url      string
+		expected string
+	}{
+		"Without query":  {"https://shop.example/sale", "https://shop.example/sale?sclid=abc"},
+		"With query":     {"https://shop.example/sale?utm_source=mail", "https://shop.example/sale?sclid=abc&utm_source=mail"},
+		"Replaced param": {"https://shop.example/?sclid=old", "https://shop.example/?sclid=abc"},
+		"With fragment":  {"https://shop.example/sale#top", "https://shop.example/sale?sclid=abc#top"},
+		"Bad url":        {"https://shop.example/%zz", "https://shop.example/%zz"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := AppendClickID(tc.url, "sclid", "abc"); res != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestRate(t *testing.T) {
+	tests := map[string]struct {
+		conversions uint64
+		visits      uint64
+		expected    float64
+	}{
+		"Without visits": {0, 0, 0},
+		"Quarter":        {1, 4, 0.25},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := Rate(tc.conversions, tc.visits); res != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, res)
+			}
+		})
+	}
+}
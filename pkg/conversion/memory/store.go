@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+)
+
+type click struct {
+	linkID uint64
+	expire time.Time
+}
+
+//Store - in-memory conversions for single instance and tests
+type Store struct {
+	mu          sync.Mutex
+	clicks      map[string]click
+	conversions map[uint64][]time.Time
+}
+
+//New ...
+func New() *Store {
+	return &Store{clicks: map[string]click{}, conversions: map[uint64][]time.Time{}}
+}
+
+//Click ...
+func (s *Store) Click(id string, linkID uint64, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, c := range s.clicks {
+		if !c.expire.After(t) {
+			delete(s.clicks, k)
+		}
+	}
+
+	s.clicks[id] = click{linkID, t.Add(conversion.Window)}
+
+	return nil
+}
+
+//Convert ...
+func (s *Store) Convert(id string, linkID uint64, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.clicks[id]
+
+	if !ok || c.linkID != linkID || !c.expire.After(t) {
+		return conversion.ErrUnknownClick
+	}
+
+	delete(s.clicks, id)
+	s.conversions[linkID] = append(s.conversions[linkID], t)
+
+	return nil
+}
+
+//Count ...
+func (s *Store) Count(linkID uint64, from, to time.Time) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res uint64
+
+	for _, t := range s.conversions[linkID] {
+		if !t.Before(from) && !t.After(to) {
+			res++
+		}
+	}
+
+	return res, nil
+}
+
+//Remove - clicks of link are removed too
+func (s *Store) Remove(linkID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conversions, linkID)
+
+	for k, c := range s.clicks {
+		if c.linkID == linkID {
+			delete(s.clicks, k)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+)
+
+func TestStore(t *testing.T) {
+	s := New()
+	now := time.Date(2020, 10, 4, 17, 18, 0, 0, time.UTC)
+
+	s.Click("a", 1, now)
+	s.Click("b", 1, now.Add(-conversion.Window))
+
+	tests := []struct {
+		name   string
+		id     string
+		linkID uint64
+		err    error
+	}{
+		{"Other link", "a", 2, conversion.ErrUnknownClick},
+		{"Success", "a", 1, nil},
+		{"Already converted", "a", 1, conversion.ErrUnknownClick},
+		{"Expired click", "b", 1, conversion.ErrUnknownClick},
+		{"Unknown click", "c", 1, conversion.ErrUnknownClick},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := s.Convert(tc.id, tc.linkID, now.Add(time.Hour)); err != tc.err {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+
+	count, _ := s.Count(1, now, now.Add(2*time.Hour))
+
+	if count != 1 {
+		t.Fatalf("Expected 1 conversion, got %v", count)
+	}
+
+	if count, _ = s.Count(1, now.Add(2*time.Hour), now.Add(3*time.Hour)); count != 0 {
+		t.Fatalf("Expected 0 conversions, got %v", count)
+	}
+
+	s.Click("d", 1, now)
+
+	if err := s.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, _ = s.Count(1, now, now.Add(2*time.Hour)); count != 0 {
+		t.Fatalf("Expected conversions to be removed, got %v", count)
+	}
+
+	if err := s.Convert("d", 1, now.Add(time.Hour)); err != conversion.ErrUnknownClick {
+		t.Fatalf("Expected click to be removed, got %v", err)
+	}
+}
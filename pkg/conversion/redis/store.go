@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+	"github.com/gomodule/redigo/redis"
+)
+
+//convertScript - take click of link and add it to conversions, conversions older than retention are trimmed
+var convertScript = redis.NewScript(2, `
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("DEL", KEYS[1])
+redis.call("ZADD", KEYS[2], ARGV[2], ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", ARGV[2] - ARGV[4])
+return 1
+`)
+
+//Store - conversions in Redis. Click is a key click:{id} with id of link which expires after attribution window,
+//conversions of link are kept in sorted set conversions:{link id} scored by unix time
+type Store struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Store {
+	return &Store{pool: pool}
+}
+
+func clickKey(id string) string {
+	return fmt.Sprintf("click:%s", id)
+}
+
+func conversionsKey(linkID uint64) string {
+	return fmt.Sprintf("conversions:%d", linkID)
+}
+
+//Click ...
+func (s *Store) Click(id string, linkID uint64, t time.Time) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("SET", clickKey(id), linkID)
+	conn.Send("EXPIREAT", clickKey(id), t.Add(conversion.Window).Unix())
+	_, err := conn.Do("EXEC")
+
+	return err
+}
+
+//Convert ...
+func (s *Store) Convert(id string, linkID uint64, t time.Time) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	ok, err := redis.Bool(convertScript.Do(
+		conn, clickKey(id), conversionsKey(linkID), linkID, t.Unix(), id, int64(conversion.Retention/time.Second),
+	))
+
+	if err == nil && !ok {
+		return conversion.ErrUnknownClick
+	}
+
+	return err
+}
+
+//Count ...
+func (s *Store) Count(linkID uint64, from, to time.Time) (uint64, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	return redis.Uint64(conn.Do("ZCOUNT", conversionsKey(linkID), from.Unix(), to.Unix()))
+}
+
+//Remove ...
+func (s *Store) Remove(linkID uint64) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", conversionsKey(linkID))
+
+	return err
+}
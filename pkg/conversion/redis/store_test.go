@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/conversion"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestStore() *Store {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func TestStoreRedis(t *testing.T) {
+	s := NewTestStore()
+	now := time.Now().Truncate(time.Second)
+
+	conn := s.pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", conversionsKey(4000), clickKey("test-click"))
+
+	if err := s.Click("test-click", 4000, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if ttl, _ := redis.Int64(conn.Do("TTL", clickKey("test-click"))); ttl <= 0 || ttl > int64(conversion.Window/time.Second) {
+		t.Fatalf("Expected click to expire after attribution window, got ttl %v", ttl)
+	}
+
+	if err := s.Convert("test-click", 4001, now); err != conversion.ErrUnknownClick {
+		t.Fatalf("Expected %v, got %v", conversion.ErrUnknownClick, err)
+	}
+
+	if err := s.Convert("test-click", 4000, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Convert("test-click", 4000, now); err != conversion.ErrUnknownClick {
+		t.Fatalf("Expected %v, got %v", conversion.ErrUnknownClick, err)
+	}
+
+	tests := map[string]struct {
+		from  time.Time
+		to    time.Time
+		count uint64
+	}{
+		"All time":     {time.Time{}, now, 1},
+		"Before click": {now.Add(-2 * time.Hour), now.Add(-time.Hour), 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			count, err := s.Count(4000, tc.from, tc.to)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != tc.count {
+				t.Fatalf("Expected %v, got %v", tc.count, count)
+			}
+		})
+	}
+	if err := s.Remove(4000); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := redis.Bool(conn.Do("EXISTS", conversionsKey(4000))); exists {
+		t.Fatalf("Expected conversions to be removed")
+	}
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+//Types of link events
+const (
+	LinkCreated  = "link.created"
+	LinkDeleted  = "link.deleted"
+	LinkVisited  = "link.visited"
+	LinkExpired  = "link.expired"
+	LinkDisabled = "link.disabled"
+	LinkEnabled  = "link.enabled"
+)
+
+//Types - all event types, can be used in validation.In
+var Types = []interface{}{LinkCreated, LinkDeleted, LinkVisited, LinkExpired, LinkDisabled, LinkEnabled}
+
+//Event - something happened with link. Link is encoded URL, URL is destination
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	LinkID    uint64    `json:"-"`
+	Link      string    `json:"link"`
+	URL       string    `json:"url,omitempty"`
+	Referrer  string    `json:"referrer,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	//Reason - disabled reason of link.disabled
+	Reason string `json:"reason,omitempty"`
+}
+
+//New - event with random ID which happened now
+func New(typ string, linkID uint64, link, url string) Event {
+	return Event{ID: NewID(), Type: typ, Time: time.Now().UTC(), LinkID: linkID, Link: link, URL: url}
+}
+
+//NewID - random hex identifier
+func NewID() string {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+//Publisher - receiver of events, e.g. webhooks
+type Publisher interface {
+	Publish(e Event) error
+}
@@ -0,0 +1,44 @@
+package events
+
+import "errors"
+
+//ErrQueueFull - event is dropped because publishers don't keep up
+var ErrQueueFull = errors.New("event queue is full")
+
+//Queue - Publisher which passes events to other publishers in background,
+//so slow publishers don't delay requests. Events are dropped when buffer is full
+type Queue struct {
+	events     chan Event
+	publishers []Publisher
+}
+
+//NewQueue - queue which buffers up to size events
+func NewQueue(size int, publishers ...Publisher) *Queue {
+	return &Queue{events: make(chan Event, size), publishers: publishers}
+}
+
+//Publish - add event to queue, doesn't block
+func (q *Queue) Publish(e Event) error {
+	select {
+	case q.events <- e:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+//Run - send queued events to publishers until Close, errors of publishers are passed to onError
+func (q *Queue) Run(onError func(e Event, err error)) {
+	for e := range q.events {
+		for _, p := range q.publishers {
+			if err := p.Publish(e); err != nil && onError != nil {
+				onError(e, err)
+			}
+		}
+	}
+}
+
+//Close - stop Run after queued events are sent, Publish must not be called after it
+func (q *Queue) Close() {
+	close(q.events)
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"errors"
+	"testing"
+)
+
+type testPublisher struct {
+	events []Event
+	err    error
+}
+
+func (p *testPublisher) Publish(e Event) error {
+	p.events = append(p.events, e)
+	return p.err
+}
+
+func TestQueue(t *testing.T) {
+	ok := &testPublisher{}
+	failing := &testPublisher{err: errors.New("stream is unavailable")}
+
+	q := NewQueue(2, failing, ok)
+
+	for _, typ := range []string{LinkCreated, LinkVisited, LinkDeleted} {
+		err := q.Publish(New(typ, 1, "b", "https://vk.com"))
+
+		if typ == LinkDeleted && err != ErrQueueFull {
+			t.Fatalf("Expected %v, got %v", ErrQueueFull, err)
+		} else if typ != LinkDeleted && err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q.Close()
+
+	errs := 0
+	q.Run(func(e Event, err error) { errs++ })
+
+	if len(ok.events) != 2 || ok.events[0].Type != LinkCreated || ok.events[1].Type != LinkVisited {
+		t.Fatalf("Expected created and visited events, got %v", ok.events)
+	}
+
+	//error of one publisher doesn't stop others
+	if errs != 2 {
+		t.Fatalf("Expected %v errors, got %v", 2, errs)
+	}
+}
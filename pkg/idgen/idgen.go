@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+//Allocator - source of ids of new links. Ids may be already taken, storage skips them
+type Allocator interface {
+	Next() (uint64, error)
+}
+
+//Checked - allocator which knows that some of its ids are not taken, storage doesn't look them up
+type Checked interface {
+	Allocator
+	//NextChecked - id and true if it is known to be not taken
+	NextChecked() (uint64, bool, error)
+}
+
+//Random - random uint64, codes are about 11 symbols long
+type Random struct{}
+
+//Next ...
+func (Random) Next() (uint64, error) {
+	return rand.Uint64(), nil
+}
+
+//Layout of snowflake id: seconds since Epoch, node and sequence number in second
+const (
+	NodeBits     = 8
+	SequenceBits = 10
+	//MaxNode ...
+	MaxNode     = 1<<NodeBits - 1
+	maxSequence = 1<<SequenceBits - 1
+)
+
+//Epoch - start of snowflake time
+var Epoch = time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+
+//Snowflake - time based ids unique across nodes without coordination. Node makes up to 1024 ids per second,
+//when sequence is exhausted or clock goes backwards the next second is used
+type Snowflake struct {
+	mu   sync.Mutex
+	node uint64
+	last int64
+	seq  uint64
+	now  func() time.Time
+}
+
+//NewSnowflake - node must be unique for every running instance
+func NewSnowflake(node uint64) (*Snowflake, error) {
+	if node > MaxNode {
+		return nil, fmt.Errorf("node must be from 0 to %d", MaxNode)
+	}
+
+	return &Snowflake{node: node, last: -1, now: time.Now}, nil
+}
+
+//Next ...
+func (s *Snowflake) Next() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := int64(s.now().Sub(Epoch) / time.Second)
+
+	if t < 0 {
+		return 0, fmt.Errorf("clock is before snowflake epoch")
+	}
+
+	if t > s.last {
+		s.last, s.seq = t, 0
+	} else if s.seq++; s.seq > maxSequence {
+		s.last, s.seq = s.last+1, 0
+	}
+
+	return uint64(s.last)<<(NodeBits+SequenceBits) | s.node<<SequenceBits | s.seq, nil
+}
@@ -0,0 +1,66 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSnowflake(t *testing.T) {
+	if _, err := NewSnowflake(MaxNode + 1); err == nil {
+		t.Fatalf("Expected error for node %v", MaxNode+1)
+	}
+}
+
+func TestSnowflake(t *testing.T) {
+	now := Epoch.Add(30 * 24 * time.Hour)
+
+	s, _ := NewSnowflake(3)
+	s.now = func() time.Time { return now }
+
+	seen := map[uint64]bool{}
+	var last uint64
+
+	//the whole sequence of second, then the next second is borrowed
+	for i := 0; i < maxSequence+3; i++ {
+		id, err := s.Next()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if seen[id] || id <= last {
+			t.Fatalf("Expected unique increasing ids, got %v after %v", id, last)
+		}
+
+		if node := id >> SequenceBits & MaxNode; node != 3 {
+			t.Fatalf("Expected node 3, got %v", node)
+		}
+
+		seen[id], last = true, id
+	}
+
+	//clock goes backwards
+	now = now.Add(-time.Hour)
+
+	if id, _ := s.Next(); id <= last {
+		t.Fatalf("Expected id greater than %v, got %v", last, id)
+	}
+
+	//codes are shorter than 9 base62 symbols for years
+	if id, _ := s.Next(); id >= 62*62*62*62*62*62*62*62 {
+		t.Fatalf("Expected id shorter than 9 symbols, got %v", id)
+	}
+
+	other, _ := NewSnowflake(4)
+	other.now = s.now
+
+	if id, _ := other.Next(); seen[id] {
+		t.Fatalf("Expected ids of other node to differ, got %v", id)
+	}
+
+	s.now = func() time.Time { return Epoch.Add(-time.Second) }
+
+	if _, err := s.Next(); err == nil {
+		t.Fatalf("Expected error for clock before epoch")
+	}
+}
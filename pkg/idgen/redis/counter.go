@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/idgen"
+	"github.com/gomodule/redigo/redis"
+)
+
+//counterKey - last allocated id, shared by Counter and Block
+const counterKey = "id:counter"
+
+//Counter - sequential ids from INCR, one round trip per id
+type Counter struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Counter {
+	return &Counter{pool: pool}
+}
+
+//Next ...
+func (c *Counter) Next() (uint64, error) {
+	conn := c.pool.Get()
+	defer conn.Close()
+
+	return redis.Uint64(conn.Do("INCR", counterKey))
+}
+
+//Block - sequential ids from ranges leased with INCRBY, one round trip per size ids.
+//Ids of range which is not used up before restart are lost
+type Block struct {
+	pool *redis.Pool
+	size uint64
+
+	mu   sync.Mutex
+	next uint64
+	end  uint64
+}
+
+//NewBlock - lease ranges of size ids
+func NewBlock(pool *redis.Pool, size uint64) *Block {
+	return &Block{pool: pool, size: size}
+}
+
+//Next ...
+func (b *Block) Next() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.next == b.end {
+		conn := b.pool.Get()
+		defer conn.Close()
+
+		end, err := redis.Uint64(conn.Do("INCRBY", counterKey, b.size))
+
+		if err != nil {
+			return 0, err
+		}
+
+		b.next, b.end = end-b.size, end
+	}
+
+	b.next++
+
+	return b.next, nil
+}
+
+//NewFromConfig - allocator by name: random, counter, block or snowflake
+func NewFromConfig(pool *redis.Pool, c *config.Config) (idgen.Allocator, error) {
+	switch c.IDAllocator {
+	case "random":
+		return idgen.Random{}, nil
+	case "counter":
+		return New(pool), nil
+	case "block":
+		if c.IDBlockSize == 0 {
+			return nil, fmt.Errorf("block size must be positive")
+		}
+		return NewBlock(pool, c.IDBlockSize), nil
+	case "snowflake":
+		s, err := idgen.NewSnowflake(c.NodeID)
+
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unknown id allocator %q", c.IDAllocator)
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/idgen"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestPool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+}
+
+func TestCounterRedis(t *testing.T) {
+	pool := NewTestPool()
+	conn := pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", counterKey)
+
+	conn.Do("DEL", counterKey)
+
+	c := New(pool)
+
+	for i := uint64(1); i <= 3; i++ {
+		if id, err := c.Next(); err != nil || id != i {
+			t.Fatalf("Expected %v, got %v %v", i, id, err)
+		}
+	}
+}
+
+func TestBlockRedis(t *testing.T) {
+	pool := NewTestPool()
+	conn := pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", counterKey)
+
+	conn.Do("DEL", counterKey)
+
+	a, b := NewBlock(pool, 10), NewBlock(pool, 10)
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		seen = map[uint64]bool{}
+	)
+
+	for _, block := range []*Block{a, b, a, b} {
+		wg.Add(1)
+		go func(block *Block) {
+			defer wg.Done()
+
+			for i := 0; i < 25; i++ {
+				id, err := block.Next()
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("Expected unique ids, got %v twice", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}(block)
+	}
+
+	wg.Wait()
+
+	//every block leases 5 ranges of 10 ids
+	if last, _ := redis.Uint64(conn.Do("GET", counterKey)); len(seen) != 100 || last != 100 {
+		t.Fatalf("Expected 100 ids from 10 ranges, got %v ids, counter %v", len(seen), last)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	pool := NewTestPool()
+
+	tests := map[string]struct {
+		conf    *config.Config
+		isError bool
+	}{
+		"Random":       {&config.Config{IDAllocator: "random"}, false},
+		"Counter":      {&config.Config{IDAllocator: "counter"}, false},
+		"Block":        {&config.Config{IDAllocator: "block", IDBlockSize: 1000}, false},
+		"Empty block":  {&config.Config{IDAllocator: "block"}, true},
+		"Snowflake":    {&config.Config{IDAllocator: "snowflake", NodeID: 1}, false},
+		"Invalid node": {&config.Config{IDAllocator: "snowflake", NodeID: idgen.MaxNode + 1}, true},
+		"Unknown":      {&config.Config{IDAllocator: "uuid"}, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ids, err := NewFromConfig(pool, tc.conf)
+
+			if tc.isError != (err != nil) || tc.isError != (ids == nil) {
+				t.Fatalf("Expected error %v, got %v %v", tc.isError, ids, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/idgen"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	poolKey = "id:pool"
+	//fillBatch - max ids in one call of fillScript
+	fillBatch = 1000
+)
+
+// KEYS[1] - pool, ARGV - ids. Ids of existing links url:{id} are skipped, so ids of pool are not taken
+var fillScript = redis.NewScript(1, `
+local added = 0
+for _, id in ipairs(ARGV) do
+	if redis.call('EXISTS', 'url:' .. id) == 0 then
+		added = added + redis.call('SADD', KEYS[1], id)
+	end
+end
+return added
+`)
+
+//KeyPool - set of unused ids which is filled from source in background, so Save only pops an id
+//and doesn't check it. If pool is empty ids are taken from source directly
+type KeyPool struct {
+	pool   *redis.Pool
+	source idgen.Allocator
+	low    int
+	size   int
+}
+
+//NewKeyPool - pool is filled up to size ids when it has less than low ids
+func NewKeyPool(pool *redis.Pool, source idgen.Allocator, low, size int) *KeyPool {
+	return &KeyPool{pool: pool, source: source, low: low, size: size}
+}
+
+//Next ...
+func (k *KeyPool) Next() (uint64, error) {
+	id, _, err := k.NextChecked()
+	return id, err
+}
+
+//NextChecked - ids of pool are not taken, ids of source must be checked
+func (k *KeyPool) NextChecked() (uint64, bool, error) {
+	conn := k.pool.Get()
+	defer conn.Close()
+
+	id, err := redis.Uint64(conn.Do("SPOP", poolKey))
+
+	if err == redis.ErrNil {
+		id, err = k.source.Next()
+		return id, false, err
+	}
+
+	return id, err == nil, err
+}
+
+//Depth - number of ids in pool
+func (k *KeyPool) Depth() (int, error) {
+	conn := k.pool.Get()
+	defer conn.Close()
+
+	return redis.Int(conn.Do("SCARD", poolKey))
+}
+
+//Fill - add ids up to size if pool is below low-water mark, returns number of added ids.
+//Ids of existing links are skipped
+func (k *KeyPool) Fill() (int, error) {
+	depth, err := k.Depth()
+
+	if err != nil || depth >= k.low {
+		return 0, err
+	}
+
+	conn := k.pool.Get()
+	defer conn.Close()
+
+	added := 0
+
+	for depth < k.size {
+		args := redis.Args{poolKey}
+
+		for i := 0; i < fillBatch && depth+i < k.size; i++ {
+			id, err := k.source.Next()
+
+			if err != nil {
+				return added, err
+			}
+
+			args = args.Add(id)
+		}
+
+		n, err := redis.Int(fillScript.Do(conn, args...))
+
+		if err != nil {
+			return added, err
+		}
+
+		added += n
+		depth += len(args) - 1
+	}
+
+	return added, nil
+}
+
+//Run - fill pool every interval until stop is closed
+func (k *KeyPool) Run(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := k.Fill(); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
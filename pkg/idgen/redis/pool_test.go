@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+func TestKeyPoolRedis(t *testing.T) {
+	pool := NewTestPool()
+	conn := pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", counterKey, poolKey)
+
+	conn.Do("DEL", counterKey, poolKey)
+
+	k := NewKeyPool(pool, NewBlock(pool, 100), 5, 1500)
+
+	tests := []struct {
+		name  string
+		pop   int
+		added int
+		depth int
+	}{
+		{"Empty pool", 0, 1500, 1500},
+		{"Above low-water mark", 1495, 0, 5},
+		{"Below low-water mark", 1, 1496, 1500},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < tc.pop; i++ {
+				if _, err := k.Next(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			added, err := k.Fill()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			depth, _ := k.Depth()
+
+			if added != tc.added || depth != tc.depth {
+				t.Fatalf("Expected %v added and depth %v, got %v and %v", tc.added, tc.depth, added, depth)
+			}
+		})
+	}
+
+	conn.Do("DEL", poolKey)
+	last, _ := redis.Uint64(conn.Do("GET", counterKey))
+
+	//empty pool takes ids from source
+	if id, err := k.Next(); err != nil || id <= last-100 {
+		t.Fatalf("Expected id from source, got %v %v", id, err)
+	}
+}
+
+//sequence - allocator which returns ids from 1
+type sequence uint64
+
+func (s *sequence) Next() (uint64, error) {
+	*s++
+	return uint64(*s), nil
+}
+
+func TestKeyPoolSkipsTakenRedis(t *testing.T) {
+	pool := NewTestPool()
+	conn := pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", poolKey, "url:2")
+
+	conn.Do("DEL", poolKey)
+	conn.Do("HSET", "url:2", "url", "https://vk.com")
+
+	k := NewKeyPool(pool, new(sequence), 1, 3)
+
+	if added, err := k.Fill(); added != 2 || err != nil {
+		t.Fatalf("Expected 2 added ids, got %v %v", added, err)
+	}
+
+	ids := map[uint64]bool{}
+
+	for i := 0; i < 3; i++ {
+		id, free, err := k.NextChecked()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		//the third id is taken from source when pool is empty
+		if free != (i < 2) {
+			t.Fatalf("Expected free %v of id %v, got %v", i < 2, id, free)
+		}
+
+		ids[id] = true
+	}
+
+	if ids[2] || !ids[1] || !ids[3] || !ids[4] {
+		t.Fatalf("Expected ids 1, 3 and 4, got %v", ids)
+	}
+}
@@ -0,0 +1,82 @@
+package leaderboard
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	//MaxTop - max number of links in one response
+	MaxTop = 100
+	//MaxWindow - visits of last MaxWindow are kept in hourly buckets
+	MaxWindow = 7 * 24 * time.Hour
+	//TrendingWindow - hourly buckets which contribute to trending score
+	TrendingWindow = 48 * time.Hour
+	//HalfLife - age of visit when its weight in trending score is halved
+	HalfLife = 6 * time.Hour
+)
+
+//ErrInvalidWindow ...
+var ErrInvalidWindow = errors.New("window: must be from 1h to 168h")
+
+//Entry - link and its score
+type Entry struct {
+	ID    uint64
+	Score float64
+}
+
+//Board - leaderboard of links by visits. Zero window means all the time
+type Board interface {
+	Hit(id uint64, t time.Time) error
+	Top(window time.Duration, limit int, now time.Time) ([]Entry, error)
+	Trending(limit int, now time.Time) ([]Entry, error)
+	Remove(ids ...uint64) error
+}
+
+//CheckWindow ...
+func CheckWindow(window time.Duration) error {
+	if window < 0 || (window > 0 && window < time.Hour) || window > MaxWindow {
+		return ErrInvalidWindow
+	}
+
+	return nil
+}
+
+//Hours - start times of hourly buckets covering window which ends at now, the latest first
+func Hours(window time.Duration, now time.Time) []time.Time {
+	n := int(math.Ceil(float64(window) / float64(time.Hour)))
+	cur := now.UTC().Truncate(time.Hour)
+
+	res := make([]time.Time, 0, n)
+
+	for i := 0; i < n; i++ {
+		res = append(res, cur.Add(-time.Duration(i)*time.Hour))
+	}
+
+	return res
+}
+
+//Weight - trending weight of bucket started at hour, current bucket has weight 1
+func Weight(hour, now time.Time) float64 {
+	age := now.UTC().Truncate(time.Hour).Sub(hour)
+
+	return math.Exp2(-float64(age) / float64(HalfLife))
+}
+
+//Sort - order entries by score descending and cut to limit
+func Sort(entries []Entry, limit int) []Entry {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries
+}
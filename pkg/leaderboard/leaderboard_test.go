@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckWindow(t *testing.T) {
+	tests := map[string]struct {
+		window time.Duration
+		err    error
+	}{
+		"All time":  {0, nil},
+		"Day":       {24 * time.Hour, nil},
+		"Week":      {MaxWindow, nil},
+		"Too short": {time.Minute, ErrInvalidWindow},
+		"Too long":  {MaxWindow + time.Hour, ErrInvalidWindow},
+		"Negative":  {-time.Hour, ErrInvalidWindow},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := CheckWindow(tc.window); err != tc.err {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestHours(t *testing.T) {
+	now := time.Date(2020, 10, 4, 17, 18, 35, 0, time.UTC)
+
+	expected := []time.Time{
+		time.Date(2020, 10, 4, 17, 0, 0, 0, time.UTC),
+		time.Date(2020, 10, 4, 16, 0, 0, 0, time.UTC),
+		time.Date(2020, 10, 4, 15, 0, 0, 0, time.UTC),
+	}
+
+	if res := Hours(150*time.Minute, now); !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
+
+func TestWeight(t *testing.T) {
+	now := time.Date(2020, 10, 4, 17, 18, 35, 0, time.UTC)
+	hour := now.Truncate(time.Hour)
+
+	tests := map[string]struct {
+		hour   time.Time
+		weight float64
+	}{
+		"Current hour":   {hour, 1},
+		"Half life":      {hour.Add(-HalfLife), 0.5},
+		"Two half lives": {hour.Add(-2 * HalfLife), 0.25},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := Weight(tc.hour, now); res != tc.weight {
+				t.Fatalf("Expected %v, got %v", tc.weight, res)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	entries := []Entry{{1, 2}, {2, 5}, {3, 2}, {4, 1}}
+	expected := []Entry{{2, 5}, {1, 2}, {3, 2}}
+
+	if res := Sort(entries, 3); !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
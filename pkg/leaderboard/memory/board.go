@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/leaderboard"
+)
+
+//Board - in-memory leaderboard for single instance and tests
+type Board struct {
+	mu    sync.Mutex
+	total map[uint64]float64
+	hours map[int64]map[uint64]float64
+}
+
+//New ...
+func New() *Board {
+	return &Board{total: map[uint64]float64{}, hours: map[int64]map[uint64]float64{}}
+}
+
+//Hit ...
+func (b *Board) Hit(id uint64, t time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hour := t.UTC().Truncate(time.Hour)
+
+	for h := range b.hours {
+		if hour.Sub(time.Unix(h, 0)) >= leaderboard.MaxWindow {
+			delete(b.hours, h)
+		}
+	}
+
+	if b.hours[hour.Unix()] == nil {
+		b.hours[hour.Unix()] = map[uint64]float64{}
+	}
+
+	b.hours[hour.Unix()][id]++
+	b.total[id]++
+
+	return nil
+}
+
+//Top ...
+func (b *Board) Top(window time.Duration, limit int, now time.Time) ([]leaderboard.Entry, error) {
+	if err := leaderboard.CheckWindow(window); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if window == 0 {
+		return entries(b.total, limit), nil
+	}
+
+	return b.union(leaderboard.Hours(window, now), limit, func(time.Time) float64 { return 1 }), nil
+}
+
+//Trending ...
+func (b *Board) Trending(limit int, now time.Time) ([]leaderboard.Entry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.union(leaderboard.Hours(leaderboard.TrendingWindow, now), limit, func(h time.Time) float64 {
+		return leaderboard.Weight(h, now)
+	}), nil
+}
+
+//Remove ...
+func (b *Board) Remove(ids ...uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, id := range ids {
+		delete(b.total, id)
+
+		for _, scores := range b.hours {
+			delete(scores, id)
+		}
+	}
+
+	return nil
+}
+
+func (b *Board) union(hours []time.Time, limit int, weight func(time.Time) float64) []leaderboard.Entry {
+	scores := map[uint64]float64{}
+
+	for _, h := range hours {
+		w := weight(h)
+
+		for id, score := range b.hours[h.Unix()] {
+			scores[id] += score * w
+		}
+	}
+
+	return entries(scores, limit)
+}
+
+func entries(scores map[uint64]float64, limit int) []leaderboard.Entry {
+	res := make([]leaderboard.Entry, 0, len(scores))
+
+	for id, score := range scores {
+		res = append(res, leaderboard.Entry{ID: id, Score: score})
+	}
+
+	return leaderboard.Sort(res, limit)
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/leaderboard"
+)
+
+func TestBoard(t *testing.T) {
+	b := New()
+	now := time.Date(2020, 10, 4, 17, 18, 35, 0, time.UTC)
+
+	// link 1 was popular 40 hours ago, link 2 is popular now
+	for i := 0; i < 10; i++ {
+		b.Hit(1, now.Add(-40*time.Hour))
+	}
+	for i := 0; i < 3; i++ {
+		b.Hit(2, now)
+	}
+	b.Hit(3, now.Add(-time.Hour))
+
+	tests := map[string]struct {
+		window   time.Duration
+		expected []leaderboard.Entry
+	}{
+		"All time": {0, []leaderboard.Entry{{ID: 1, Score: 10}, {ID: 2, Score: 3}, {ID: 3, Score: 1}}},
+		"Day":      {24 * time.Hour, []leaderboard.Entry{{ID: 2, Score: 3}, {ID: 3, Score: 1}}},
+		"Hour":     {time.Hour, []leaderboard.Entry{{ID: 2, Score: 3}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := b.Top(tc.window, 10, now)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, res)
+			}
+		})
+	}
+
+	trending, err := b.Trending(10, now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trending) != 3 || trending[0].ID != 2 || trending[1].ID != 3 || trending[2].ID != 1 {
+		t.Fatalf("Expected links 2, 3, 1 in trending, got %v", trending)
+	}
+
+	b.Remove(2)
+
+	res, _ := b.Top(0, 10, now)
+	expected := []leaderboard.Entry{{ID: 1, Score: 10}, {ID: 3, Score: 1}}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+}
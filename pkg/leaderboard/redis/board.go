@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/leaderboard"
+	"github.com/gomodule/redigo/redis"
+)
+
+const totalKey = "top:total"
+
+//resultTTL - lifetime of union of hourly buckets in seconds
+const resultTTL = 10
+
+//Board - leaderboard in Redis. All time visits are kept in sorted set top:total,
+//visits of every hour in sorted sets top:{hour start} which expire after MaxWindow
+type Board struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Board {
+	return &Board{pool: pool}
+}
+
+func hourKey(hour time.Time) string {
+	return fmt.Sprintf("top:%d", hour.Unix())
+}
+
+//Hit ...
+func (b *Board) Hit(id uint64, t time.Time) error {
+	conn := b.pool.Get()
+	defer conn.Close()
+
+	hour := t.UTC().Truncate(time.Hour)
+
+	conn.Send("MULTI")
+	conn.Send("ZINCRBY", totalKey, 1, id)
+	conn.Send("ZINCRBY", hourKey(hour), 1, id)
+	conn.Send("EXPIREAT", hourKey(hour), hour.Add(leaderboard.MaxWindow+time.Hour).Unix())
+	_, err := conn.Do("EXEC")
+
+	return err
+}
+
+//Top ...
+func (b *Board) Top(window time.Duration, limit int, now time.Time) ([]leaderboard.Entry, error) {
+	if err := leaderboard.CheckWindow(window); err != nil {
+		return nil, err
+	}
+
+	if window == 0 {
+		conn := b.pool.Get()
+		defer conn.Close()
+
+		return entries(redis.Strings(conn.Do("ZREVRANGE", totalKey, 0, limit-1, "WITHSCORES")))
+	}
+
+	hours := leaderboard.Hours(window, now)
+	dst := fmt.Sprintf("top:window:%d:%d", len(hours), hours[0].Unix())
+
+	return b.union(dst, hours, limit, func(time.Time) float64 { return 1 })
+}
+
+//Trending ...
+func (b *Board) Trending(limit int, now time.Time) ([]leaderboard.Entry, error) {
+	hours := leaderboard.Hours(leaderboard.TrendingWindow, now)
+	dst := fmt.Sprintf("top:trending:%d", hours[0].Unix())
+
+	return b.union(dst, hours, limit, func(h time.Time) float64 {
+		return leaderboard.Weight(h, now)
+	})
+}
+
+//Remove ...
+func (b *Board) Remove(ids ...uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	conn := b.pool.Get()
+	defer conn.Close()
+
+	members := redis.Args{}.AddFlat(ids)
+
+	conn.Send("MULTI")
+	conn.Send("ZREM", redis.Args{totalKey}.Add(members...)...)
+
+	for _, h := range leaderboard.Hours(leaderboard.MaxWindow+time.Hour, time.Now()) {
+		conn.Send("ZREM", redis.Args{hourKey(h)}.Add(members...)...)
+	}
+
+	_, err := conn.Do("EXEC")
+
+	return err
+}
+
+//union - weighted sum of hourly buckets stored in dst for a few seconds
+func (b *Board) union(dst string, hours []time.Time, limit int, weight func(time.Time) float64) ([]leaderboard.Entry, error) {
+	conn := b.pool.Get()
+	defer conn.Close()
+
+	args := redis.Args{dst, len(hours)}
+	weights := redis.Args{"WEIGHTS"}
+
+	for _, h := range hours {
+		args = args.Add(hourKey(h))
+		weights = weights.Add(strconv.FormatFloat(weight(h), 'g', -1, 64))
+	}
+
+	conn.Send("MULTI")
+	conn.Send("ZUNIONSTORE", append(args, weights...)...)
+	conn.Send("EXPIRE", dst, resultTTL)
+	conn.Send("ZREVRANGE", dst, 0, limit-1, "WITHSCORES")
+
+	values, err := redis.Values(conn.Do("EXEC"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries(redis.Strings(values[2], nil))
+}
+
+//entries - parse reply of ZREVRANGE WITHSCORES
+func entries(values []string, err error) ([]leaderboard.Entry, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]leaderboard.Entry, 0, len(values)/2)
+
+	for i := 0; i+1 < len(values); i += 2 {
+		id, err := strconv.ParseUint(values[i], 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		score, err := strconv.ParseFloat(values[i+1], 64)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, leaderboard.Entry{ID: id, Score: score})
+	}
+
+	return res, nil
+}
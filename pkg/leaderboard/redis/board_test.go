@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/leaderboard"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestBoard() *Board {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func TestBoardRedis(t *testing.T) {
+	b := NewTestBoard()
+	ids := []uint64{2000, 2001, 2002}
+	defer b.Remove(ids...)
+
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		b.Hit(ids[0], now.Add(-40*time.Hour))
+	}
+	for i := 0; i < 3; i++ {
+		b.Hit(ids[1], now)
+	}
+	b.Hit(ids[2], now.Add(-time.Hour))
+
+	res, err := b.Top(24*time.Hour, 10, now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []leaderboard.Entry{{ID: ids[1], Score: 3}, {ID: ids[2], Score: 1}}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+
+	trending, err := b.Trending(10, now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trending) != 3 || trending[0].ID != ids[1] || trending[1].ID != ids[2] || trending[2].ID != ids[0] {
+		t.Fatalf("Expected links %v in trending, got %v", ids, trending)
+	}
+
+	if err = b.Remove(ids[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := b.pool.Get()
+	defer conn.Close()
+
+	if _, err := redis.Float64(conn.Do("ZSCORE", totalKey, ids[0])); err != redis.ErrNil {
+		t.Fatalf("Expected removed link, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package live
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/analytics"
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/useragent"
+)
+
+//buffer - visits buffered for every subscriber, visits are dropped for slow subscribers
+const buffer = 32
+
+//Visit - visit of link in live feed without personal data
+type Visit struct {
+	LinkID   uint64    `json:"-"`
+	Time     time.Time `json:"time"`
+	Referrer string    `json:"referrer"`
+	Device   string    `json:"device"`
+}
+
+//NewVisit - visit from link.visited event
+func NewVisit(e events.Event) Visit {
+	return Visit{
+		LinkID:   e.LinkID,
+		Time:     e.Time,
+		Referrer: analytics.ReferrerDomain(e.Referrer),
+		Device:   useragent.Parse(e.UserAgent).Device,
+	}
+}
+
+//Hub - fan out of visits to subscribers of this instance
+type Hub struct {
+	mu   sync.Mutex
+	subs map[uint64]map[chan Visit]struct{}
+}
+
+//NewHub ...
+func NewHub() *Hub {
+	return &Hub{subs: map[uint64]map[chan Visit]struct{}{}}
+}
+
+//Subscribe - visits of link, cancel must be called when subscriber leaves
+func (h *Hub) Subscribe(id uint64) (<-chan Visit, func()) {
+	ch := make(chan Visit, buffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subs[id] == nil {
+		h.subs[id] = map[chan Visit]struct{}{}
+	}
+	h.subs[id][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		delete(h.subs[id], ch)
+		if len(h.subs[id]) == 0 {
+			delete(h.subs, id)
+		}
+	}
+}
+
+//Subscribers - number of subscribers of link
+func (h *Hub) Subscribers(id uint64) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subs[id])
+}
+
+//Broadcast - send visit to subscribers of its link
+func (h *Hub) Broadcast(v Visit) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[v.LinkID] {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
+
+//Publish - broadcast link.visited events, so Hub can be used as events.Publisher on single instance
+func (h *Hub) Publish(e events.Event) error {
+	if e.Type == events.LinkVisited {
+		h.Broadcast(NewVisit(e))
+	}
+
+	return nil
+}
+
+//Relay - delivers visits to hubs of all instances
+type Relay interface {
+	events.Publisher
+	//Listen - broadcast visits from other instances to hub until stop is closed or error happens
+	Listen(h *Hub, stop <-chan struct{}) error
+}
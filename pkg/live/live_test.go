@@ -0,0 +1,66 @@
+package live
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+)
+
+func TestHub(t *testing.T) {
+	h := NewHub()
+
+	first, cancelFirst := h.Subscribe(1)
+	second, cancelSecond := h.Subscribe(1)
+	defer cancelSecond()
+	other, cancelOther := h.Subscribe(2)
+	defer cancelOther()
+
+	e := events.New(events.LinkVisited, 1, "b", "https://vk.com")
+	e.Referrer = "https://www.google.com/search?q=1"
+	e.UserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 14_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Mobile/15E148 Safari/604.1"
+
+	h.Publish(e)
+	h.Publish(events.New(events.LinkCreated, 1, "b", "https://vk.com"))
+
+	for _, ch := range []<-chan Visit{first, second} {
+		select {
+		case v := <-ch:
+			if v.LinkID != 1 || v.Referrer != "google.com" || v.Device != "mobile" || !v.Time.Equal(e.Time) {
+				t.Fatalf("Unexpected visit %v", v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected visit")
+		}
+	}
+
+	select {
+	case v := <-other:
+		t.Fatalf("Expected no visits of other link, got %v", v)
+	case v := <-first:
+		t.Fatalf("Expected only visited event, got %v", v)
+	default:
+	}
+
+	cancelFirst()
+
+	if n := h.Subscribers(1); n != 1 {
+		t.Fatalf("Expected 1 subscriber, got %v", n)
+	}
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	h := NewHub()
+
+	ch, cancel := h.Subscribe(1)
+	defer cancel()
+
+	// broadcast doesn't block when buffer is full
+	for i := 0; i < 2*buffer; i++ {
+		h.Broadcast(Visit{LinkID: 1})
+	}
+
+	if len(ch) != buffer {
+		t.Fatalf("Expected %v buffered visits, got %v", buffer, len(ch))
+	}
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/live"
+	"github.com/gomodule/redigo/redis"
+)
+
+const channelPrefix = "live:"
+
+//Relay - visits are published to channel live:{id}, every instance listens to live:*
+//and broadcasts them to its subscribers
+type Relay struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Relay {
+	return &Relay{pool: pool}
+}
+
+//Publish ...
+func (r *Relay) Publish(e events.Event) error {
+	if e.Type != events.LinkVisited {
+		return nil
+	}
+
+	data, err := json.Marshal(live.NewVisit(e))
+
+	if err != nil {
+		return err
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("PUBLISH", fmt.Sprintf("%s%d", channelPrefix, e.LinkID), data)
+
+	return err
+}
+
+//Listen ...
+func (r *Relay) Listen(h *live.Hub, stop <-chan struct{}) error {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	psc := redis.PubSubConn{Conn: conn}
+
+	if err := psc.PSubscribe(channelPrefix + "*"); err != nil {
+		return err
+	}
+
+	// connection is closed only after unsubscribing goroutine is finished
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	wg.Add(1)
+	defer wg.Wait()
+	defer close(done)
+
+	go func() {
+		defer wg.Done()
+
+		select {
+		case <-stop:
+			psc.PUnsubscribe()
+		case <-done:
+		}
+	}()
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			id, err := strconv.ParseUint(strings.TrimPrefix(v.Channel, channelPrefix), 10, 64)
+
+			if err != nil {
+				continue
+			}
+
+			visit := live.Visit{}
+
+			if err := json.Unmarshal(v.Data, &visit); err != nil {
+				continue
+			}
+
+			// link id is not part of feed, it is taken from channel
+			visit.LinkID = id
+			h.Broadcast(visit)
+		case redis.Subscription:
+			if v.Count == 0 {
+				return nil
+			}
+		case error:
+			return v
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/live"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestRelay() *Relay {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func TestRelayRedis(t *testing.T) {
+	r := NewTestRelay()
+	h := live.NewHub()
+
+	ch, cancel := h.Subscribe(5000)
+	defer cancel()
+
+	stop := make(chan struct{})
+	done := make(chan error)
+
+	go func() {
+		done <- r.Listen(h, stop)
+	}()
+
+	e := events.New(events.LinkVisited, 5000, "b", "https://vk.com")
+	e.Referrer = "https://t.co/abc"
+
+	// subscription is asynchronous, publish until visit is received
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(2 * time.Second)
+
+	for received := false; !received; {
+		select {
+		case <-ticker.C:
+			if err := r.Publish(e); err != nil {
+				t.Fatal(err)
+			}
+		case v := <-ch:
+			if v.LinkID != 5000 || v.Referrer != "t.co" {
+				t.Fatalf("Unexpected visit %v", v)
+			}
+			received = true
+		case <-timeout:
+			t.Fatalf("Expected visit from relay")
+		}
+	}
+
+	close(stop)
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -23,3 +23,10 @@ func (lwr *LoggerWR) WriteHeader(statusCode int) {
 	lwr.StatusCode = statusCode
 	lwr.W.WriteHeader(statusCode)
 }
+
+//Flush wrapper for original ResponseWriter Flush, it is required by streaming responses
+func (lwr *LoggerWR) Flush() {
+	if f, ok := lwr.W.(http.Flusher); ok {
+		f.Flush()
+	}
+}
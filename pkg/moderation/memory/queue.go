@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/moderation"
+)
+
+type entry struct {
+	reporters map[string]bool
+	reports   []moderation.Report
+}
+
+//Queue - in-memory moderation queue
+type Queue struct {
+	mu      sync.Mutex
+	entries map[uint64]*entry
+}
+
+//New ...
+func New() *Queue {
+	return &Queue{entries: map[uint64]*entry{}}
+}
+
+//Report ...
+func (q *Queue) Report(id uint64, r moderation.Report) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e, ok := q.entries[id]
+
+	if !ok {
+		e = &entry{reporters: map[string]bool{}}
+		q.entries[id] = e
+	}
+
+	if e.reporters[r.Reporter] {
+		return nil
+	}
+
+	e.reporters[r.Reporter] = true
+	e.reports = append([]moderation.Report{r}, e.reports...)
+
+	if len(e.reports) > moderation.MaxReports {
+		e.reports = e.reports[:moderation.MaxReports]
+	}
+
+	return nil
+}
+
+//List ...
+func (q *Queue) List(offset, limit int) ([]moderation.Entry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	res := make([]moderation.Entry, 0, len(q.entries))
+
+	for id, e := range q.entries {
+		recent := make([]moderation.Report, len(e.reports))
+		copy(recent, e.reports)
+
+		res = append(res, moderation.Entry{ID: id, Reports: len(e.reporters), Recent: recent})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Reports != res[j].Reports {
+			return res[i].Reports > res[j].Reports
+		}
+		return res[i].ID < res[j].ID
+	})
+
+	if offset >= len(res) {
+		return []moderation.Entry{}, nil
+	}
+
+	res = res[offset:]
+
+	if limit < len(res) {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+//Resolve ...
+func (q *Queue) Resolve(id uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.entries, id)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/moderation"
+)
+
+func TestQueue(t *testing.T) {
+	q := New()
+
+	reports := []struct {
+		id       uint64
+		reporter string
+	}{
+		{1, "1.1.1.1"},
+		{2, "1.1.1.1"},
+		{2, "2.2.2.2"},
+		{2, "2.2.2.2"},
+		{3, "1.1.1.1"},
+	}
+
+	for _, r := range reports {
+		err := q.Report(r.id, moderation.Report{Reason: "spam", Time: time.Now(), Reporter: r.reporter})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, _ := q.List(0, 2)
+
+	if len(entries) != 2 || entries[0].ID != 2 || entries[0].Reports != 2 || len(entries[0].Recent) != 2 || entries[1].ID != 1 {
+		t.Fatalf("Unexpected first page %v", entries)
+	}
+
+	entries, _ = q.List(2, 2)
+
+	if len(entries) != 1 || entries[0].ID != 3 {
+		t.Fatalf("Unexpected second page %v", entries)
+	}
+
+	q.Resolve(2)
+
+	entries, _ = q.List(0, 10)
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries after resolve, got %v", entries)
+	}
+}
@@ -0,0 +1,35 @@
+package moderation
+
+import (
+	"time"
+)
+
+//Reasons which visitors can choose in report
+var Reasons = []interface{}{"spam", "phishing", "malware", "illegal", "other"}
+
+//MaxReports - number of recent reports kept for every link
+const MaxReports = 20
+
+//Report - visitor complaint about link
+type Report struct {
+	Reason   string    `json:"reason"`
+	Comment  string    `json:"comment,omitempty"`
+	Time     time.Time `json:"time"`
+	Reporter string    `json:"-"`
+}
+
+//Entry - reported link in moderation queue
+type Entry struct {
+	ID      uint64   `json:"-"`
+	Reports int      `json:"reports"`
+	Recent  []Report `json:"recent"`
+}
+
+//Queue - links waiting for moderator decision, most reported first
+type Queue interface {
+	//Report - add report to queue. Repeated reports from the same reporter are not counted
+	Report(id uint64, r Report) error
+	List(offset, limit int) ([]Entry, error)
+	//Resolve - remove link and its reports from queue
+	Resolve(id uint64) error
+}
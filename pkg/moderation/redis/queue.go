@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/moderation"
+	"github.com/gomodule/redigo/redis"
+)
+
+const queueKey = "moderation:queue"
+
+//Queue - moderation queue in Redis. Links are kept in sorted set by number of reporters
+type Queue struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Queue {
+	return &Queue{pool: pool}
+}
+
+func reportsKey(id uint64) string {
+	return fmt.Sprintf("moderation:reports:%d", id)
+}
+
+func reportersKey(id uint64) string {
+	return fmt.Sprintf("moderation:reporters:%d", id)
+}
+
+//Report ...
+func (q *Queue) Report(id uint64, r moderation.Report) error {
+	data, err := json.Marshal(r)
+
+	if err != nil {
+		return err
+	}
+
+	conn := q.pool.Get()
+	defer conn.Close()
+
+	added, err := redis.Bool(conn.Do("SADD", reportersKey(id), r.Reporter))
+
+	if err != nil || !added {
+		return err
+	}
+
+	conn.Send("MULTI")
+	conn.Send("ZINCRBY", queueKey, 1, id)
+	conn.Send("LPUSH", reportsKey(id), data)
+	conn.Send("LTRIM", reportsKey(id), 0, moderation.MaxReports-1)
+	_, err = conn.Do("EXEC")
+
+	return err
+}
+
+//List ...
+func (q *Queue) List(offset, limit int) ([]moderation.Entry, error) {
+	conn := q.pool.Get()
+	defer conn.Close()
+
+	values, err := redis.Strings(conn.Do("ZREVRANGE", queueKey, offset, offset+limit-1, "WITHSCORES"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]moderation.Entry, 0, len(values)/2)
+
+	for i := 0; i+1 < len(values); i += 2 {
+		id, err := strconv.ParseUint(values[i], 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		count, err := strconv.Atoi(values[i+1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		reports, err := redis.ByteSlices(conn.Do("LRANGE", reportsKey(id), 0, -1))
+
+		if err != nil {
+			return nil, err
+		}
+
+		entry := moderation.Entry{ID: id, Reports: count, Recent: make([]moderation.Report, 0, len(reports))}
+
+		for _, data := range reports {
+			r := moderation.Report{}
+
+			if err := json.Unmarshal(data, &r); err != nil {
+				return nil, err
+			}
+
+			entry.Recent = append(entry.Recent, r)
+		}
+
+		res = append(res, entry)
+	}
+
+	return res, nil
+}
+
+//Resolve ...
+func (q *Queue) Resolve(id uint64) error {
+	conn := q.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("ZREM", queueKey, id)
+	conn.Send("DEL", reportsKey(id), reportersKey(id))
+	_, err := conn.Do("EXEC")
+
+	return err
+}
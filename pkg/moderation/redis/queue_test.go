@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/moderation"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestQueue() *Queue {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func TestQueueRedis(t *testing.T) {
+	q := NewTestQueue()
+	defer q.Resolve(1)
+	defer q.Resolve(2)
+
+	reports := []struct {
+		id       uint64
+		reporter string
+		comment  string
+	}{
+		{1, "1.1.1.1", "first"},
+		{2, "1.1.1.1", "second"},
+		{2, "2.2.2.2", "third"},
+		{2, "2.2.2.2", "repeated"},
+	}
+
+	for _, r := range reports {
+		err := q.Report(r.id, moderation.Report{Reason: "spam", Comment: r.comment, Time: time.Now(), Reporter: r.reporter})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := q.List(0, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 || entries[0].ID != 2 || entries[0].Reports != 2 || entries[1].ID != 1 {
+		t.Fatalf("Unexpected moderation list %v", entries)
+	}
+
+	if len(entries[0].Recent) != 2 || entries[0].Recent[0].Comment != "third" {
+		t.Fatalf("Unexpected recent reports %v", entries[0].Recent)
+	}
+
+	if err = q.Resolve(2); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ = q.List(0, 10)
+
+	if len(entries) != 1 || entries[0].ID != 1 {
+		t.Fatalf("Unexpected moderation list after resolve %v", entries)
+	}
+}
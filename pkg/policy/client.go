@@ -0,0 +1,40 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+//NewClient - HTTP client for user supplied targets which refuses to connect to private addresses unless allowPrivate is set.
+//Addresses are checked on connect, so host can't resolve to other address after target is checked
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	if !allowPrivate {
+		dialer.Control = RefusePrivate
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+//RefusePrivate - net.Dialer Control which fails on private addresses
+func RefusePrivate(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || IsPrivate(ip) {
+		return fmt.Errorf("address %s is not allowed", host)
+	}
+
+	return nil
+}
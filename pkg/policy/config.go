@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+)
+
+//NewFromConfig - create Engine with rules enabled in config
+func NewFromConfig(c *config.Config) (*Engine, error) {
+	e := New(Schemes(c.AllowedSchemes...))
+
+	self := c.SelfHosts
+	if c.Host != "" && c.Host != "0.0.0.0" && c.Host != "::" {
+		self = append(self, c.Host)
+	}
+	e.Add(SelfHosts(self...))
+
+	if c.BlocklistFile != "" {
+		b, err := LoadBlocklist(c.BlocklistFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e.Add(b)
+	}
+
+	if !c.AllowPrivateNetworks {
+		e.Add(PrivateNetworks(nil))
+	}
+
+	return e, nil
+}
+
+//TargetsFromConfig - Engine for URLs which are called by the server itself, e.g. alert webhooks.
+//Only http and https are allowed, private addresses are rejected unless they are allowed in config
+func TargetsFromConfig(c *config.Config) *Engine {
+	e := New(Schemes("http", "https"))
+
+	if !c.AllowPrivateNetworks {
+		e.Add(PrivateNetworks(nil))
+	}
+
+	return e
+}
@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"net/url"
+	"strings"
+)
+
+//Violation - reason why URL is rejected
+type Violation struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+//Rule - single check of destination URL. Returns nil if URL is allowed
+type Rule interface {
+	Check(u *url.URL) *Violation
+}
+
+//RuleFunc - adapter to use ordinary function as Rule
+type RuleFunc func(u *url.URL) *Violation
+
+//Check ...
+func (f RuleFunc) Check(u *url.URL) *Violation {
+	return f(u)
+}
+
+//Engine - checks URL against all rules
+type Engine struct {
+	rules []Rule
+}
+
+//New ...
+func New(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+//Add - append rule to engine
+func (e *Engine) Add(r Rule) {
+	e.rules = append(e.rules, r)
+}
+
+//Check - returns all violations of URL, empty result means URL is allowed
+func (e *Engine) Check(rawURL string) []Violation {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return []Violation{{"url", "invalid url"}}
+	}
+
+	var res []Violation
+
+	for _, r := range e.rules {
+		if v := r.Check(u); v != nil {
+			res = append(res, *v)
+		}
+	}
+
+	return res
+}
+
+//hostname - lowercase host without port and trailing dot
+func hostname(u *url.URL) string {
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
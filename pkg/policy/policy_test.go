@@ -0,0 +1,114 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testResolver(host string) ([]net.IP, error) {
+	switch host {
+	case "vk.com":
+		return []net.IP{net.ParseIP("87.240.190.78")}, nil
+	case "intranet.corp":
+		return []net.IP{net.ParseIP("87.240.190.78"), net.ParseIP("192.168.1.10")}, nil
+	}
+	return nil, fmt.Errorf("no such host")
+}
+
+func TestEngineCheck(t *testing.T) {
+	e := New(
+		Schemes("http", "https"),
+		SelfHosts("short.ly"),
+		NewBlocklist("evil.com"),
+		PrivateNetworks(testResolver),
+	)
+
+	tests := map[string]struct {
+		url   string
+		rules []string
+	}{
+		"Allowed URL":              {"https://vk.com/feed", nil},
+		"Unknown host is allowed":  {"https://unknown.host", nil},
+		"Scheme is not allowed":    {"ftp://vk.com", []string{"scheme"}},
+		"Link to shortener":        {"http://SHORT.LY./abc", []string{"self"}},
+		"Blocked domain":           {"https://evil.com", []string{"blocklist"}},
+		"Blocked subdomain":        {"https://www.evil.com/login", []string{"blocklist"}},
+		"Not blocked suffix":       {"https://notevil.com", nil},
+		"Loopback address":         {"http://127.0.0.1:8080", []string{"private_network"}},
+		"IPv6 loopback":            {"http://[::1]/", []string{"private_network"}},
+		"Link-local address":       {"http://169.254.169.254/latest/meta-data", []string{"private_network"}},
+		"RFC1918 address":          {"http://10.1.2.3", []string{"private_network"}},
+		"Host resolves to private": {"http://intranet.corp", []string{"private_network"}},
+		"Several violations":       {"javascript://127.0.0.1", []string{"scheme", "private_network"}},
+		"Invalid url":              {"http://[::1", []string{"url"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var rules []string
+
+			for _, v := range e.Check(tc.url) {
+				rules = append(rules, v.Rule)
+			}
+
+			if !reflect.DeepEqual(rules, tc.rules) {
+				t.Fatalf("Expected %v, got %v", tc.rules, rules)
+			}
+		})
+	}
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	f, err := ioutil.TempFile("", "blocklist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString("# phishing\nevil.com\n\n  Bad.Org  \n")
+	f.Close()
+
+	b, err := LoadBlocklist(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]bool{"evil.com": true, "bad.org": true}
+
+	if !reflect.DeepEqual(b.domains, expected) {
+		t.Fatalf("Expected %v, got %v", expected, b.domains)
+	}
+
+	if _, err := LoadBlocklist(f.Name() + ".not_found"); err == nil {
+		t.Fatalf("Expected error for missing file, but got nil")
+	}
+}
+
+func TestLookupTimeout(t *testing.T) {
+	//DNS server which never answers
+	slow := &net.Resolver{PreferGo: true, Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	start := time.Now()
+	_, err := LookupIP(slow, 50*time.Millisecond)("intranet.corp")
+
+	if err == nil {
+		t.Fatalf("Expected lookup error, but got nil")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Expected lookup to stop after timeout, got %v", elapsed)
+	}
+
+	if v := New(PrivateNetworks(LookupIP(slow, 50*time.Millisecond))).Check("http://intranet.corp"); len(v) > 0 {
+		t.Fatalf("Expected host which can't be resolved to be allowed, got %v", v)
+	}
+}
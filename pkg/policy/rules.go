@@ -0,0 +1,202 @@
+package policy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+//Schemes - allow only listed URL schemes
+func Schemes(allowed ...string) Rule {
+	set := map[string]bool{}
+
+	for _, s := range allowed {
+		set[strings.ToLower(s)] = true
+	}
+
+	return RuleFunc(func(u *url.URL) *Violation {
+		if !set[strings.ToLower(u.Scheme)] {
+			return &Violation{"scheme", fmt.Sprintf("scheme %q is not allowed", u.Scheme)}
+		}
+		return nil
+	})
+}
+
+//SelfHosts - reject links to the shortener itself, they create redirect loops
+func SelfHosts(hosts ...string) Rule {
+	set := map[string]bool{}
+
+	for _, h := range hosts {
+		set[strings.TrimSuffix(strings.ToLower(h), ".")] = true
+	}
+
+	return RuleFunc(func(u *url.URL) *Violation {
+		if set[hostname(u)] {
+			return &Violation{"self", "link to the shortener itself is not allowed"}
+		}
+		return nil
+	})
+}
+
+//Blocklist - rejects blocked domains and all their subdomains
+type Blocklist struct {
+	domains map[string]bool
+}
+
+//NewBlocklist ...
+func NewBlocklist(domains ...string) *Blocklist {
+	b := &Blocklist{domains: map[string]bool{}}
+
+	for _, d := range domains {
+		b.domains[strings.TrimSuffix(strings.ToLower(d), ".")] = true
+	}
+
+	return b
+}
+
+//LoadBlocklist - read blocklist file with one domain per line. Empty lines and lines started with # are skipped
+func LoadBlocklist(file string) (*Blocklist, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var domains []string
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domains = append(domains, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewBlocklist(domains...), nil
+}
+
+//Check ...
+func (b *Blocklist) Check(u *url.URL) *Violation {
+	host := hostname(u)
+
+	for host != "" {
+		if b.domains[host] {
+			return &Violation{"blocklist", fmt.Sprintf("domain %q is blocked", host)}
+		}
+
+		i := strings.IndexByte(host, '.')
+		if i == -1 {
+			break
+		}
+		host = host[i+1:]
+	}
+
+	return nil
+}
+
+var privateNetworks = func() []*net.IPNet {
+	var res []*net.IPNet
+
+	for _, cidr := range []string{
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"fc00::/7",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		res = append(res, n)
+	}
+
+	return res
+}()
+
+//IsPrivate - loopback, RFC1918, unique local, link-local or unspecified address
+func IsPrivate(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return true
+	}
+
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Resolver - returns addresses of host
+type Resolver func(host string) ([]net.IP, error)
+
+//LookupTimeout - time of DNS lookup of default Resolver, policy is checked while link is created
+const LookupTimeout = 2 * time.Second
+
+//LookupIP - Resolver which gives up after timeout, r is net.DefaultResolver if it is nil
+func LookupIP(r *net.Resolver, timeout time.Duration) Resolver {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+
+	return func(host string) ([]net.IP, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		addrs, err := r.LookupIPAddr(ctx, host)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ips := make([]net.IP, 0, len(addrs))
+
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+
+		return ips, nil
+	}
+}
+
+//PrivateNetworks - reject hosts which resolve to private addresses. Hosts which can't be resolved are allowed.
+//Default resolver is LookupIP with LookupTimeout
+func PrivateNetworks(resolve Resolver) Rule {
+	if resolve == nil {
+		resolve = LookupIP(nil, LookupTimeout)
+	}
+
+	return RuleFunc(func(u *url.URL) *Violation {
+		host := hostname(u)
+
+		ips := []net.IP{net.ParseIP(host)}
+
+		if ips[0] == nil {
+			var err error
+			if ips, err = resolve(host); err != nil {
+				return nil
+			}
+		}
+
+		for _, ip := range ips {
+			if IsPrivate(ip) {
+				return &Violation{"private_network", fmt.Sprintf("host %q resolves to private address %s", host, ip)}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+)
+
+//sweepEvery - number of Allow calls between removing stale counters
+const sweepEvery = 1000
+
+type counter struct {
+	window int64
+	period time.Duration
+	prev   int
+	cur    int
+}
+
+//Limiter - in-memory sliding window limiter for single instance deployments
+type Limiter struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	calls    int
+	now      func() time.Time
+}
+
+//New ...
+func New() *Limiter {
+	return &Limiter{counters: map[string]*counter{}, now: time.Now}
+}
+
+//Allow - check limit for key and count request if it is allowed
+func (l *Limiter) Allow(key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	now := l.now()
+	window := ratelimit.Window(now, limit.Period)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	c, ok := l.counters[key]
+
+	if !ok || c.period != limit.Period {
+		c = &counter{window: window, period: limit.Period}
+		l.counters[key] = c
+	}
+
+	switch window - c.window {
+	case 0:
+	case 1:
+		c.prev, c.cur = c.cur, 0
+	default:
+		c.prev, c.cur = 0, 0
+	}
+	c.window = window
+
+	res := ratelimit.Evaluate(limit, now, c.prev, c.cur)
+
+	if res.Allowed {
+		c.cur++
+	}
+
+	return res, nil
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	for key, c := range l.counters {
+		if ratelimit.Window(now, c.period)-c.window > 1 {
+			delete(l.counters, key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Unix(6000, 0)
+	l := New()
+	l.now = func() time.Time { return now }
+
+	limit := ratelimit.Limit{Requests: 3, Period: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		res, err := l.Allow("client", limit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !res.Allowed {
+			t.Fatalf("Request %d must be allowed", i)
+		}
+
+		if res.Remaining != 2-i {
+			t.Fatalf("Expected remaining %d, got %d", 2-i, res.Remaining)
+		}
+	}
+
+	res, _ := l.Allow("client", limit)
+
+	if res.Allowed {
+		t.Fatalf("Request over limit must be denied")
+	}
+
+	if res.RetryAfter <= 0 {
+		t.Fatalf("Expected positive retry after, got %v", res.RetryAfter)
+	}
+
+	res, _ = l.Allow("other", limit)
+
+	if !res.Allowed {
+		t.Fatalf("Other client must be allowed")
+	}
+
+	now = now.Add(3 * time.Minute)
+	res, _ = l.Allow("client", limit)
+
+	if !res.Allowed {
+		t.Fatalf("Request must be allowed after window is over")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Unix(6000, 0)
+	l := New()
+	l.now = func() time.Time { return now }
+
+	limit := ratelimit.Limit{Requests: 3, Period: time.Minute}
+
+	l.Allow("client", limit)
+	now = now.Add(3 * time.Minute)
+	l.sweep(now)
+
+	if len(l.counters) != 0 {
+		t.Fatalf("Expected stale counters to be removed, got %d", len(l.counters))
+	}
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Limit - allowed number of requests per period. Zero limit means unlimited
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+//Parse - parse limit in format "requests/period", e.g. "100/1m"
+func Parse(s string) (Limit, error) {
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+
+	parts := strings.SplitN(s, "/", 2)
+
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit: %q", s)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit: %q", s)
+	}
+
+	period, err := time.ParseDuration(parts[1])
+
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit: %q", s)
+	}
+
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+//UnmarshalText - allows to use Limit in config
+func (l *Limit) UnmarshalText(text []byte) error {
+	res, err := Parse(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*l = res
+
+	return nil
+}
+
+//Unlimited ...
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0 || l.Period == 0
+}
+
+//Result - limiter decision
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+//Limiter ...
+type Limiter interface {
+	Allow(key string, limit Limit) (*Result, error)
+}
+
+//Window - index of fixed window which contains t
+func Window(t time.Time, period time.Duration) int64 {
+	return t.UnixNano() / int64(period)
+}
+
+//Evaluate - sliding window decision based on counters of current and previous fixed windows.
+//Previous window is weighted by the part of it which still overlaps the sliding window
+func Evaluate(limit Limit, now time.Time, prev, cur int) *Result {
+	elapsed := time.Duration(now.UnixNano() % int64(limit.Period))
+	reset := limit.Period - elapsed
+	weight := 1 - float64(elapsed)/float64(limit.Period)
+
+	estimated := float64(prev)*weight + float64(cur)
+
+	res := &Result{Limit: limit.Requests, Reset: reset}
+
+	if estimated+1 <= float64(limit.Requests) {
+		res.Allowed = true
+		res.Remaining = int(math.Floor(float64(limit.Requests) - estimated - 1))
+		return res
+	}
+
+	res.RetryAfter = retryAfter(limit, elapsed, prev, cur).Round(time.Millisecond)
+
+	return res
+}
+
+//retryAfter - time until estimated count drops low enough to allow one more request
+func retryAfter(limit Limit, elapsed time.Duration, prev, cur int) time.Duration {
+	free := float64(limit.Requests - cur - 1)
+
+	if free >= 0 && prev > 0 {
+		fraction := 1 - free/float64(prev)
+		return time.Duration(fraction*float64(limit.Period)) - elapsed
+	}
+
+	// current window is full, wait for the next one where it becomes previous
+	wait := limit.Period - elapsed
+
+	if limit.Requests > 0 && cur > 0 {
+		fraction := 1 - float64(limit.Requests-1)/float64(cur)
+		if fraction > 0 {
+			wait += time.Duration(fraction * float64(limit.Period))
+		}
+	}
+
+	return wait
+}
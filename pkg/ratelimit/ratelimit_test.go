@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		inp     string
+		limit   Limit
+		isError bool
+	}{
+		"Requests per minute": {"100/1m", Limit{100, time.Minute}, false},
+		"Empty is unlimited":  {"", Limit{}, false},
+		"Zero is unlimited":   {"0", Limit{}, false},
+		"Error! No period":    {"100", Limit{}, true},
+		"Error! Bad requests": {"abc/1m", Limit{}, true},
+		"Error! Bad period":   {"100/abc", Limit{}, true},
+		"Error! Zero period":  {"100/0s", Limit{}, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := Parse(tc.inp)
+
+			if tc.isError && err == nil {
+				t.Fatalf("Expected error, but got nil")
+			}
+
+			if !tc.isError && err != nil {
+				t.Fatalf("Expected nil, but got %v", err)
+			}
+
+			if res != tc.limit {
+				t.Fatalf("Expected %v, got %v", tc.limit, res)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	limit := Limit{10, time.Minute}
+	// a quarter of current window is elapsed
+	now := time.Unix(0, 0).Add(100*time.Minute + 15*time.Second)
+
+	tests := map[string]struct {
+		prev       int
+		cur        int
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		"Empty windows":               {0, 0, true, 9, 0},
+		"Previous window is weighted": {8, 3, true, 0, 0},
+		"Previous window denies":      {8, 4, false, 0, 7500 * time.Millisecond},
+		"Current window is full":      {0, 10, false, 0, 45*time.Second + 6*time.Second},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := Evaluate(limit, now, tc.prev, tc.cur)
+
+			if res.Allowed != tc.allowed {
+				t.Fatalf("Expected allowed %v, got %v", tc.allowed, res.Allowed)
+			}
+
+			if res.Remaining != tc.remaining {
+				t.Fatalf("Expected remaining %v, got %v", tc.remaining, res.Remaining)
+			}
+
+			if res.RetryAfter != tc.retryAfter {
+				t.Fatalf("Expected retry after %v, got %v", tc.retryAfter, res.RetryAfter)
+			}
+
+			if res.Reset != 45*time.Second {
+				t.Fatalf("Expected reset %v, got %v", 45*time.Second, res.Reset)
+			}
+		})
+	}
+}
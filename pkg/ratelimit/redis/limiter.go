@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+	"github.com/gomodule/redigo/redis"
+)
+
+// KEYS[1] - current window counter, KEYS[2] - previous window counter
+// ARGV[1] - limit, ARGV[2] - previous window weight, ARGV[3] - counter ttl in ms
+var allowScript = redis.NewScript(2, `
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+if prev * tonumber(ARGV[2]) + cur + 1 > tonumber(ARGV[1]) then
+	return {0, cur, prev}
+end
+cur = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, cur - 1, prev}
+`)
+
+//Limiter - sliding window limiter shared between instances through Redis
+type Limiter struct {
+	pool *redis.Pool
+	now  func() time.Time
+}
+
+//New ...
+func New(pool *redis.Pool) *Limiter {
+	return &Limiter{pool: pool, now: time.Now}
+}
+
+//Allow - check limit for key and count request if it is allowed
+func (l *Limiter) Allow(key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	now := l.now()
+	window := ratelimit.Window(now, limit.Period)
+	elapsed := now.UnixNano() % int64(limit.Period)
+	weight := 1 - float64(elapsed)/float64(limit.Period)
+
+	conn := l.pool.Get()
+	defer conn.Close()
+
+	values, err := redis.Int64s(allowScript.Do(conn,
+		fmt.Sprintf("rl:%s:%d", key, window),
+		fmt.Sprintf("rl:%s:%d", key, window-1),
+		limit.Requests,
+		weight,
+		(2 * limit.Period).Milliseconds(),
+	))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ratelimit.Evaluate(limit, now, int(values[2]), int(values[1])), nil
+}
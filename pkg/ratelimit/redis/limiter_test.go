@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/ratelimit"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestLimiter(now time.Time) *Limiter {
+	l := New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+	l.now = func() time.Time { return now }
+
+	return l
+}
+
+func removeCounters(l *Limiter, key string, limit ratelimit.Limit) {
+	conn := l.pool.Get()
+	defer conn.Close()
+
+	window := ratelimit.Window(l.now(), limit.Period)
+	conn.Do("DEL", fmt.Sprintf("rl:%s:%d", key, window), fmt.Sprintf("rl:%s:%d", key, window-1))
+}
+
+func TestAllowRedisLimiter(t *testing.T) {
+	l := NewTestLimiter(time.Unix(6000, 0))
+	limit := ratelimit.Limit{Requests: 2, Period: time.Minute}
+	key := "test:client"
+
+	defer removeCounters(l, key, limit)
+
+	tests := []struct {
+		allowed   bool
+		remaining int
+	}{
+		{true, 1},
+		{true, 0},
+		{false, 0},
+	}
+
+	for i, tc := range tests {
+		res, err := l.Allow(key, limit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Allowed != tc.allowed {
+			t.Fatalf("Request %d: expected allowed %v, got %v", i, tc.allowed, res.Allowed)
+		}
+
+		if res.Remaining != tc.remaining {
+			t.Fatalf("Request %d: expected remaining %v, got %v", i, tc.remaining, res.Remaining)
+		}
+	}
+}
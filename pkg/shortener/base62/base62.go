@@ -1,7 +1,6 @@
 package base62
 
 import (
-	"errors"
 	"math"
 	"strings"
 
@@ -11,9 +10,12 @@ import (
 const (
 	alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	length   = uint64(len(alphabet))
+	//maxLength - length of code of max uint64
+	maxLength = 11
 )
 
-//Base62 ...
+//Base62 - digits are written from the least significant, so the last symbol of code is never zero digit 'a'.
+//Only id 0 is encoded as "a"
 type Base62 struct{}
 
 //New -  create new Base62 Shortener
@@ -23,8 +25,12 @@ func New() shortener.Shortener {
 
 //Encode ...
 func (b *Base62) Encode(number uint64) string {
+	if number == 0 {
+		return alphabet[:1]
+	}
+
 	var encodedBuilder strings.Builder
-	encodedBuilder.Grow(11)
+	encodedBuilder.Grow(maxLength)
 
 	for ; number > 0; number = number / length {
 		encodedBuilder.WriteByte(alphabet[(number % length)])
@@ -33,17 +39,37 @@ func (b *Base62) Encode(number uint64) string {
 	return encodedBuilder.String()
 }
 
-//Decode ...
+//Decode - decode canonical code, returns *shortener.CodeError for invalid, overflowing and non-canonical codes
 func (b *Base62) Decode(encoded string) (uint64, error) {
+	if encoded == "" {
+		return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrEmpty}
+	}
+
+	for _, symbol := range encoded {
+		if strings.IndexRune(alphabet, symbol) == -1 {
+			return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrInvalidCharacter, Detail: string(symbol)}
+		}
+	}
+
+	if len(encoded) > 1 && encoded[len(encoded)-1] == alphabet[0] {
+		return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrNonCanonical}
+	}
+
+	if len(encoded) > maxLength {
+		return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrOverflow}
+	}
+
 	var number uint64
 
-	for i, symbol := range encoded {
-		alphabeticPosition := strings.IndexRune(alphabet, symbol)
+	//Horner's method from the most significant digit, number*length+digit must not exceed max uint64
+	for i := len(encoded) - 1; i >= 0; i-- {
+		digit := uint64(strings.IndexByte(alphabet, encoded[i]))
 
-		if alphabeticPosition == -1 {
-			return uint64(alphabeticPosition), errors.New("invalid character: " + string(symbol))
+		if number > (math.MaxUint64-digit)/length {
+			return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrOverflow}
 		}
-		number += uint64(alphabeticPosition) * uint64(math.Pow(float64(length), float64(i)))
+
+		number = number*length + digit
 	}
 
 	return number, nil
@@ -1,7 +1,12 @@
 package base62
 
 import (
+	"errors"
+	"math"
 	"testing"
+	"testing/quick"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
 )
 
 var (
@@ -58,3 +63,90 @@ func TestEncoder(t *testing.T) {
 		}
 	}
 }
+
+func TestDecoderErrors(t *testing.T) {
+	b := New()
+
+	tests := map[string]struct {
+		code string
+		err  error
+	}{
+		"Empty":            {"", shortener.ErrEmpty},
+		"Invalid symbol":   {"Ub-m0af", shortener.ErrInvalidCharacter},
+		"Non-ASCII symbol": {"Ubrmöaf", shortener.ErrInvalidCharacter},
+		"Trailing zero":    {"h4Ca", shortener.ErrNonCanonical},
+		"Only zeros":       {"aa", shortener.ErrNonCanonical},
+		"Max uint64 + 1":   {"qIrkgbKrQ8v", shortener.ErrOverflow},
+		"Top digit":        {"aaaaaaaaaa9", shortener.ErrOverflow},
+		"Too long":         {"bbbbbbbbbbbb", shortener.ErrOverflow},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := b.Decode(tc.code)
+
+			if !errors.Is(err, tc.err) || !shortener.IsInvalidCode(err) {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestBounds(t *testing.T) {
+	b := New()
+
+	tests := map[string]struct {
+		number uint64
+		code   string
+	}{
+		"Zero":       {0, "a"},
+		"Last digit": {61, "9"},
+		"Two digits": {62, "ab"},
+		"Max uint64": {math.MaxUint64, "pIrkgbKrQ8v"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := b.Encode(tc.number); res != tc.code {
+				t.Fatalf("Expected %v, got %v", tc.code, res)
+			}
+
+			res, err := b.Decode(tc.code)
+
+			if err != nil || res != tc.number {
+				t.Fatalf("Expected %v, got %v %v", tc.number, res, err)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	b := New()
+
+	err := quick.Check(func(number uint64) bool {
+		res, err := b.Decode(b.Encode(number))
+		return err == nil && res == number
+	}, &quick.Config{MaxCount: 100000})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeIsCanonical(t *testing.T) {
+	b := New()
+
+	//any decoded code is the only code of its number
+	err := quick.Check(func(code []byte) bool {
+		for i := range code {
+			code[i] = alphabet[int(code[i])%len(alphabet)]
+		}
+
+		res, err := b.Decode(string(code))
+		return err != nil || b.Encode(res) == string(code)
+	}, &quick.Config{MaxCount: 100000})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
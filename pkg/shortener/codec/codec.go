@@ -0,0 +1,189 @@
+package codec
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+)
+
+//Alphabets of named codecs
+const (
+	Base62 = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	//Base58 - without look-alike 0, O, I and l
+	Base58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	//Base36 - digits and lower case letters, decoded case-insensitively
+	Base36 = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+//unreserved - symbols which are not escaped in URL path. Dot is excluded, codes "." and ".." are cleaned from path by router
+const unreserved = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_~"
+
+//Codec - positional codec with arbitrary alphabet. Digits are written from the least significant like in base62,
+//codes shorter than minimal length are padded with zero digit, optional check character is the last one
+type Codec struct {
+	alphabet string
+	//index - digit of symbol plus one, 0 for symbols not in alphabet
+	index     [256]uint8
+	base      uint64
+	maxLength int
+	minLength int
+	fold      bool
+	check     bool
+}
+
+//Option ...
+type Option func(*Codec)
+
+//MinLength - pad codes with zero digit to n symbols
+func MinLength(n int) Option {
+	return func(c *Codec) {
+		c.minLength = n
+	}
+}
+
+//CaseInsensitive - decode upper case letters as lower case ones, alphabet must not contain upper case letters
+func CaseInsensitive() Option {
+	return func(c *Codec) {
+		c.fold = true
+	}
+}
+
+//CheckCharacter - append Luhn mod N check character to codes, so single mistyped symbol or
+//transposition of adjacent symbols is detected without lookup
+func CheckCharacter() Option {
+	return func(c *Codec) {
+		c.check = true
+	}
+}
+
+//New - create codec, alphabet must have at least 2 unique symbols which are not escaped in URL
+func New(alphabet string, opts ...Option) (*Codec, error) {
+	c := &Codec{alphabet: alphabet, base: uint64(len(alphabet))}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if len(alphabet) < 2 {
+		return nil, fmt.Errorf("alphabet must have at least 2 symbols")
+	}
+
+	for i := 0; i < len(alphabet); i++ {
+		symbol := alphabet[i]
+
+		if strings.IndexByte(unreserved, symbol) == -1 {
+			return nil, fmt.Errorf("symbol %q is not allowed in alphabet", symbol)
+		}
+
+		if c.index[symbol] != 0 {
+			return nil, fmt.Errorf("symbol %q is repeated in alphabet", symbol)
+		}
+
+		if c.fold && symbol >= 'A' && symbol <= 'Z' {
+			return nil, fmt.Errorf("case-insensitive alphabet has upper case symbol %q", symbol)
+		}
+
+		c.index[symbol] = uint8(i + 1)
+	}
+
+	if c.minLength < 0 {
+		return nil, fmt.Errorf("min length must not be negative")
+	}
+
+	for n := uint64(math.MaxUint64); n > 0; n /= c.base {
+		c.maxLength++
+	}
+
+	if c.minLength > c.maxLength {
+		return nil, fmt.Errorf("min length must not be greater than %d", c.maxLength)
+	}
+
+	return c, nil
+}
+
+//Encode ...
+func (c *Codec) Encode(number uint64) string {
+	var b strings.Builder
+	b.Grow(c.maxLength)
+
+	for number > 0 || b.Len() == 0 {
+		b.WriteByte(c.alphabet[number%c.base])
+		number /= c.base
+	}
+
+	for b.Len() < c.minLength {
+		b.WriteByte(c.alphabet[0])
+	}
+
+	if c.check {
+		b.WriteByte(c.alphabet[c.checkDigit(b.String())])
+	}
+
+	return b.String()
+}
+
+//checkDigit - Luhn mod N digit of code, every second digit from the right is doubled
+func (c *Codec) checkDigit(code string) uint64 {
+	var sum uint64
+	factor := uint64(2)
+
+	for i := len(code) - 1; i >= 0; i-- {
+		addend := factor * uint64(c.index[code[i]]-1)
+		sum += addend/c.base + addend%c.base
+
+		factor = 3 - factor
+	}
+
+	return (c.base - sum%c.base) % c.base
+}
+
+//Decode - decode canonical code, returns *shortener.CodeError for invalid, overflowing and non-canonical codes
+func (c *Codec) Decode(encoded string) (uint64, error) {
+	if encoded == "" {
+		return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrEmpty}
+	}
+
+	code := encoded
+	if c.fold {
+		code = strings.ToLower(code)
+	}
+
+	for _, symbol := range code {
+		if symbol >= 256 || c.index[symbol] == 0 {
+			return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrInvalidCharacter, Detail: string(symbol)}
+		}
+	}
+
+	if c.check {
+		if len(code) < 2 || c.checkDigit(code[:len(code)-1]) != uint64(c.index[code[len(code)-1]]-1) {
+			return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrCheckCharacter}
+		}
+
+		code = code[:len(code)-1]
+	}
+
+	//only padding may end with zero digit
+	if len(code) < c.minLength || (len(code) > c.minLength && len(code) > 1 && code[len(code)-1] == c.alphabet[0]) {
+		return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrNonCanonical}
+	}
+
+	if len(code) > c.maxLength {
+		return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrOverflow}
+	}
+
+	var number uint64
+
+	for i := len(code) - 1; i >= 0; i-- {
+		digit := uint64(c.index[code[i]] - 1)
+
+		if number > (math.MaxUint64-digit)/c.base {
+			return 0, &shortener.CodeError{Code: encoded, Err: shortener.ErrOverflow}
+		}
+
+		number = number*c.base + digit
+	}
+
+	return number, nil
+}
@@ -0,0 +1,154 @@
+package codec
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
+)
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		alphabet string
+		opts     []Option
+		isError  bool
+	}{
+		"Base58":                {Base58, nil, false},
+		"Binary":                {"01", []Option{MinLength(64)}, false},
+		"One symbol":            {"a", nil, true},
+		"Repeated symbol":       {"abca", nil, true},
+		"Escaped symbol":        {"ab/", nil, true},
+		"Dot":                   {"ab.", nil, true},
+		"Upper case folded":     {"abC", []Option{CaseInsensitive()}, true},
+		"Too long padding":      {Base62, []Option{MinLength(12)}, true},
+		"Negative padding":      {Base62, []Option{MinLength(-1)}, true},
+		"Case insensitive base": {Base36, []Option{CaseInsensitive()}, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(tc.alphabet, tc.opts...)
+
+			if tc.isError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.isError, err)
+			}
+		})
+	}
+}
+
+func TestCodec(t *testing.T) {
+	b58, _ := New(Base58)
+	b36, _ := New(Base36, CaseInsensitive())
+	padded, _ := New(Base62, MinLength(6))
+
+	tests := map[string]struct {
+		codec  *Codec
+		code   string
+		number uint64
+		err    error
+	}{
+		"Base58":                {b58, "k6", 333, nil},
+		"Base58 look-alike":     {b58, "k0", 0, shortener.ErrInvalidCharacter},
+		"Base36":                {b36, "k6", 236, nil},
+		"Base36 upper case":     {b36, "K6", 236, nil},
+		"Base36 trailing zero":  {b36, "k60", 0, shortener.ErrNonCanonical},
+		"Padded":                {padded, "h4Caaa", 111111, nil},
+		"Padded zero":           {padded, "aaaaaa", 0, nil},
+		"Too short":             {padded, "h4C", 0, shortener.ErrNonCanonical},
+		"Too long padding":      {padded, "h4Caaaa", 0, shortener.ErrNonCanonical},
+		"Longer than padding":   {padded, "Ubrm0af", 284772472784, nil},
+		"Base36 overflow":       {b36, "0000000000005", 0, shortener.ErrOverflow},
+		"Base36 max uint64 + 1": {b36, "gsgs46211e5w3", 0, shortener.ErrOverflow},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := tc.codec.Decode(tc.code)
+
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+
+			if res != tc.number {
+				t.Fatalf("Expected %v, got %v", tc.number, res)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	b62, _ := New(Base62)
+	b58, _ := New(Base58, MinLength(5))
+	b36, _ := New(Base36, CaseInsensitive())
+	binary, _ := New("01", MinLength(64))
+	legacy := base62.New()
+
+	err := quick.Check(func(number uint64) bool {
+		for _, c := range []*Codec{b62, b58, b36, binary} {
+			code := c.Encode(number)
+
+			if res, err := c.Decode(code); err != nil || res != number || len(code) < c.minLength {
+				return false
+			}
+		}
+
+		return b62.Encode(number) == legacy.Encode(number)
+	}, &quick.Config{MaxCount: 50000})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCharacter(t *testing.T) {
+	c, err := New(Base58, MinLength(4), CheckCharacter())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = quick.Check(func(number uint64) bool {
+		code := c.Encode(number)
+		res, err := c.Decode(code)
+
+		if err != nil || res != number {
+			return false
+		}
+
+		//every mistyped symbol is detected
+		for i := 0; i < len(code); i++ {
+			for j := 0; j < len(Base58); j++ {
+				typo := code[:i] + Base58[j:j+1] + code[i+1:]
+
+				if _, err := c.Decode(typo); typo != code && !errors.Is(err, shortener.ErrCheckCharacter) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}, &quick.Config{MaxCount: 1000})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		code string
+		err  error
+	}{
+		"Only check character": {"1", shortener.ErrCheckCharacter},
+		"Part of code":         {c.Encode(333)[2:], shortener.ErrCheckCharacter},
+		"Invalid symbol":       {"k0111", shortener.ErrInvalidCharacter},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Decode(tc.code); !errors.Is(err, tc.err) {
+				t.Fatalf("Expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/feistel"
+)
+
+//Named - codec by name: base62, base58, base36 or custom with alphabet
+func Named(name, alphabet string, opts ...Option) (*Codec, error) {
+	switch name {
+	case "base62":
+		return New(Base62, opts...)
+	case "base58":
+		return New(Base58, opts...)
+	case "base36":
+		return New(Base36, append(opts, CaseInsensitive())...)
+	case "custom":
+		return New(alphabet, opts...)
+	}
+
+	return nil, fmt.Errorf("unknown codec %q", name)
+}
+
+//NewFromConfig - codec of new codes, ids are permuted with the first of CodecKeys.
+//Old keys and codecs of existing codes are used for decoding after it
+func NewFromConfig(c *config.Config) (shortener.Shortener, error) {
+	opts := []Option{MinLength(c.CodecMinLength)}
+	if c.CodecCheck {
+		opts = append(opts, CheckCharacter())
+	}
+
+	primary, err := Named(c.Codec, c.CodecAlphabet, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var codecs []shortener.Shortener
+
+	for _, key := range c.CodecKeys {
+		f, err := feistel.New(primary, []byte(key))
+
+		if err != nil {
+			return nil, err
+		}
+
+		codecs = append(codecs, f)
+	}
+
+	if len(codecs) == 0 {
+		codecs = append(codecs, primary)
+	}
+
+	//plain - primary codec decodes the same codes as codec with its name
+	plain := c.CodecMinLength == 0 && !c.CodecCheck && len(c.CodecKeys) == 0
+
+	for _, name := range c.DecodeCodecs {
+		if name == "" || (name == c.Codec && plain) {
+			continue
+		}
+
+		previous, err := Named(name, c.CodecAlphabet)
+
+		if err != nil {
+			return nil, err
+		}
+
+		codecs = append(codecs, previous)
+	}
+
+	if len(codecs) == 1 {
+		return codecs[0], nil
+	}
+
+	return shortener.NewMultiDecoder(codecs...), nil
+}
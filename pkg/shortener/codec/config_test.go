@@ -0,0 +1,111 @@
+package codec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
+	"github.com/caarlos0/env/v6"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	tests := map[string]struct {
+		conf    *config.Config
+		multi   bool
+		isError bool
+	}{
+		"Default":           {&config.Config{Codec: "base62", DecodeCodecs: []string{"base62"}}, false, false},
+		"Padded base62":     {&config.Config{Codec: "base62", CodecMinLength: 8, DecodeCodecs: []string{"base62"}}, true, false},
+		"Base58":            {&config.Config{Codec: "base58", DecodeCodecs: []string{"base62"}}, true, false},
+		"Without previous":  {&config.Config{Codec: "base36"}, false, false},
+		"Custom":            {&config.Config{Codec: "custom", CodecAlphabet: "0123456789"}, false, false},
+		"Custom is empty":   {&config.Config{Codec: "custom"}, false, true},
+		"Unknown codec":     {&config.Config{Codec: "base64"}, false, true},
+		"Unknown previous":  {&config.Config{Codec: "base58", DecodeCodecs: []string{"base64"}}, false, true},
+		"Permuted":          {&config.Config{Codec: "base62", CodecKeys: []string{"0123456789abcdef"}}, false, false},
+		"Rotated key":       {&config.Config{Codec: "base62", CodecKeys: []string{"0123456789abcdef", "fedcba9876543210"}}, true, false},
+		"Permuted existing": {&config.Config{Codec: "base62", CodecKeys: []string{"0123456789abcdef"}, DecodeCodecs: []string{"base62"}}, true, false},
+		"Empty previous":    {&config.Config{Codec: "base58", DecodeCodecs: []string{""}}, false, false},
+		"Check character":   {&config.Config{Codec: "base62", CodecCheck: true, DecodeCodecs: []string{"base62"}}, true, false},
+		"Short key":         {&config.Config{Codec: "base62", CodecKeys: []string{"secret"}}, false, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := NewFromConfig(tc.conf)
+
+			if tc.isError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.isError, err)
+			}
+
+			if _, ok := res.(*shortener.MultiDecoder); err == nil && ok != tc.multi {
+				t.Fatalf("Expected MultiDecoder %v, got %T", tc.multi, res)
+			}
+		})
+	}
+}
+
+func TestCheckCharacterWithDefaults(t *testing.T) {
+	conf := &config.Config{}
+	if err := env.Parse(conf); err != nil {
+		t.Fatal(err)
+	}
+	conf.CodecCheck = true
+
+	s, err := NewFromConfig(conf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, ok := s.(*shortener.MultiDecoder)
+
+	if !ok {
+		t.Fatalf("Expected MultiDecoder, got %T", s)
+	}
+
+	//links created before the check are decoded by DECODE_CODECS
+	tests := map[string]struct {
+		code string
+		id   uint64
+	}{
+		"New code":      {s.Encode(12345), 12345},
+		"Existing code": {base62.New().Encode(12345), 12345},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ids, err := m.DecodeAll(tc.code)
+
+			if err != nil || !contains(ids, tc.id) {
+				t.Fatalf("Expected %v among %v, got %v", tc.id, ids, err)
+			}
+		})
+	}
+
+	//without previous codecs mistyped code is rejected
+	conf.DecodeCodecs = nil
+
+	s, err = NewFromConfig(conf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := s.Encode(12345)
+
+	if _, err := s.Decode("z" + code[1:]); !errors.Is(err, shortener.ErrCheckCharacter) {
+		t.Fatalf("Expected %v, got %v", shortener.ErrCheckCharacter, err)
+	}
+}
+
+func contains(ids []uint64, id uint64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package denylist
+
+import (
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+)
+
+//NewFromConfig - deny list of DenyWords and DenyWordsFile, reserved words are always denied
+func NewFromConfig(c *config.Config) (*Denylist, error) {
+	if c.DenyWordsFile != "" {
+		return Load(c.DenyWordsFile, c.DenyWords...)
+	}
+
+	return New(c.DenyWords...), nil
+}
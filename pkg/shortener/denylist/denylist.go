@@ -0,0 +1,120 @@
+package denylist
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/idgen"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+)
+
+//Reserved - first segments of routes, code equal to one of them is shadowed by route
+var Reserved = []string{"admin", "encode", "info", "links", "metrics", "px", "stats", "top", "trending"}
+
+//maxAttempts - ids which are tried before Next gives up, it happens only if deny list matches almost every code
+const maxAttempts = 100
+
+//lookalikes - digits which are read as letters in codes
+var lookalikes = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t")
+
+//Denylist - codes which must not be given to links: reserved words and codes containing deny words
+type Denylist struct {
+	reserved map[string]bool
+	words    []string
+}
+
+//New - words are matched case-insensitively anywhere in code, also when letters are written with look-alike digits
+func New(words ...string) *Denylist {
+	d := &Denylist{reserved: map[string]bool{}}
+
+	for _, r := range Reserved {
+		d.reserved[r] = true
+	}
+
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			d.words = append(d.words, w)
+		}
+	}
+
+	return d
+}
+
+//Load - read deny words file with one word per line. Empty lines and lines started with # are skipped
+func Load(file string, words ...string) (*Denylist, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words = append(words, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return New(words...), nil
+}
+
+//Allowed - code is not reserved and doesn't contain deny words. Reserved words are matched case-sensitively
+//like routes, so "Top" is not shadowed by /top
+func (d *Denylist) Allowed(code string) bool {
+	if d.reserved[code] {
+		return false
+	}
+
+	code = strings.ToLower(code)
+	plain := lookalikes.Replace(code)
+
+	for _, w := range d.words {
+		if strings.Contains(code, w) || strings.Contains(plain, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Allocator - skips ids which are encoded to denied codes
+type Allocator struct {
+	ids       idgen.Allocator
+	shortener shortener.Shortener
+	list      *Denylist
+}
+
+//Filter - ids of allocator with codes allowed by d
+func (d *Denylist) Filter(ids idgen.Allocator, s shortener.Shortener) *Allocator {
+	return &Allocator{ids: ids, shortener: s, list: d}
+}
+
+//Next ...
+func (a *Allocator) Next() (uint64, error) {
+	for i := 0; i < maxAttempts; i++ {
+		id, err := a.ids.Next()
+
+		if err != nil {
+			return 0, err
+		}
+
+		if a.list.Allowed(a.shortener.Encode(id)) {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no allowed code in %d ids", maxAttempts)
+}
@@ -0,0 +1,105 @@
+package denylist
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	d := New("darn", " Heck ")
+
+	tests := map[string]struct {
+		code    string
+		allowed bool
+	}{
+		"Plain code":         {"Ubrm0af", true},
+		"Route":              {"top", false},
+		"Route upper case":   {"Top", true},
+		"Route all caps":     {"TOP", true},
+		"Route inside code":  {"xtopx", true},
+		"Word":               {"darn", false},
+		"Word inside code":   {"xyDaRnz", false},
+		"Look-alike digits":  {"h3ck", false},
+		"Trimmed word":       {"aheckb", false},
+		"Part of word":       {"dar", true},
+		"Digits of no words": {"1234", true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if allowed := d.Allowed(tc.code); allowed != tc.allowed {
+				t.Fatalf("Expected %v, got %v", tc.allowed, allowed)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	f, err := ioutil.TempFile("", "denywords")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString("# words\ndarn\n\n  HECK  \n")
+	f.Close()
+
+	d, err := Load(f.Name(), "drat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"drat", "darn", "heck"}
+
+	if !reflect.DeepEqual(d.words, expected) {
+		t.Fatalf("Expected %v, got %v", expected, d.words)
+	}
+
+	if _, err := Load(f.Name() + ".not_found"); err == nil {
+		t.Fatalf("Expected error for missing file, but got nil")
+	}
+}
+
+//sequence - ids from 1
+type sequence struct {
+	last uint64
+}
+
+func (s *sequence) Next() (uint64, error) {
+	s.last++
+	return s.last, nil
+}
+
+//words - id 1 is "top", id 2 is "darn", others are numbers
+type words struct{}
+
+func (words) Encode(id uint64) string {
+	switch id {
+	case 1:
+		return "top"
+	case 2:
+		return "darn"
+	}
+	return strconv.FormatUint(id, 10)
+}
+
+func (words) Decode(code string) (uint64, error) {
+	return strconv.ParseUint(code, 10, 64)
+}
+
+func TestFilter(t *testing.T) {
+	ids := New("darn").Filter(&sequence{}, words{})
+
+	id, err := ids.Next()
+
+	if err != nil || id != 3 {
+		t.Fatalf("Expected %v, got %v %v", 3, id, err)
+	}
+
+	if _, err = New("a", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9").Filter(&sequence{}, words{}).Next(); err == nil {
+		t.Fatalf("Expected error when every code is denied, but got nil")
+	}
+}
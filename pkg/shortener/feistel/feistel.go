@@ -0,0 +1,130 @@
+package feistel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+)
+
+const (
+	//rounds - 4 rounds are enough for strong pseudorandom permutation of large blocks (Luby-Rackoff),
+	//but bounds of the proof are poor for blocks of a few bits which are used for small ids, so the number is doubled
+	rounds = 8
+	//MinKeyLength ...
+	MinKeyLength = 16
+)
+
+//Feistel - shortener which permutes id with keyed Feistel network before encoding, so adjacent ids
+//have unrelated codes. Ids are permuted within domain of ids with the same bit length (see domain),
+//so codes of small ids stay short. Permutation is a bijection of uint64, every code of inner codec is decoded to some id
+type Feistel struct {
+	codec shortener.Shortener
+	key   []byte
+}
+
+//New - permute ids with key before encoding them with codec
+func New(codec shortener.Shortener, key []byte) (*Feistel, error) {
+	if len(key) < MinKeyLength {
+		return nil, fmt.Errorf("key must have at least %d bytes", MinKeyLength)
+	}
+
+	return &Feistel{codec: codec, key: key}, nil
+}
+
+//domain - size of block in bits for id and the least id of the domain. Domain is [min, 1<<size),
+//size is bit length of id rounded up to even number, so block is split into equal halves
+func domain(id uint64) (uint, uint64) {
+	size := uint(bits.Len64(id))
+	size += size % 2
+
+	if size <= 2 {
+		return 2, 0
+	}
+
+	return size, 1 << (size - 2)
+}
+
+//round - pseudorandom function of round, HMAC-SHA256 of block size, round number and half of block
+func (f *Feistel) round(size uint, i int, half uint64) uint64 {
+	var data [6]byte
+
+	data[0] = byte(size)
+	data[1] = byte(i)
+	binary.BigEndian.PutUint32(data[2:], uint32(half))
+
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write(data[:])
+
+	return uint64(binary.BigEndian.Uint32(mac.Sum(nil)))
+}
+
+//permute - Feistel network over block of size bits
+func (f *Feistel) permute(size uint, block uint64) uint64 {
+	half := size / 2
+	mask := uint64(1)<<half - 1
+	left, right := block>>half, block&mask
+
+	for i := 0; i < rounds; i++ {
+		left, right = right, (left^f.round(size, i, right))&mask
+	}
+
+	return left<<half | right
+}
+
+//restore - inverse of permute
+func (f *Feistel) restore(size uint, block uint64) uint64 {
+	half := size / 2
+	mask := uint64(1)<<half - 1
+	left, right := block>>half, block&mask
+
+	for i := rounds - 1; i >= 0; i-- {
+		left, right = (right^f.round(size, i, left))&mask, left
+	}
+
+	return left<<half | right
+}
+
+//Permute - the block is permuted again while it is out of domain of id (cycle walking).
+//At least a quarter of block values are in domain, so it takes a few steps
+func (f *Feistel) Permute(id uint64) uint64 {
+	size, min := domain(id)
+
+	number := f.permute(size, id)
+	for number < min {
+		number = f.permute(size, number)
+	}
+
+	return number
+}
+
+//Restore - inverse of Permute
+func (f *Feistel) Restore(number uint64) uint64 {
+	size, min := domain(number)
+
+	id := f.restore(size, number)
+	for id < min {
+		id = f.restore(size, id)
+	}
+
+	return id
+}
+
+//Encode ...
+func (f *Feistel) Encode(id uint64) string {
+	return f.codec.Encode(f.Permute(id))
+}
+
+//Decode ...
+func (f *Feistel) Decode(code string) (uint64, error) {
+	number, err := f.codec.Decode(code)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return f.Restore(number), nil
+}
@@ -0,0 +1,97 @@
+package feistel
+
+import (
+	"math/bits"
+	"testing"
+	"testing/quick"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
+)
+
+func NewTestFeistel(t *testing.T, key string) *Feistel {
+	f, err := New(base62.New(), []byte(key))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(base62.New(), []byte("short")); err == nil {
+		t.Fatalf("Expected error for short key")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	f := NewTestFeistel(t, "0123456789abcdef")
+
+	err := quick.Check(func(id uint64) bool {
+		res, err := f.Decode(f.Encode(id))
+		return err == nil && res == id && f.Restore(f.Permute(id)) == id
+	}, &quick.Config{MaxCount: 50000})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = f.Decode("Ubrm0afa"); !shortener.IsInvalidCode(err) {
+		t.Fatalf("Expected error of codec, got %v", err)
+	}
+}
+
+func TestAdjacentIDs(t *testing.T) {
+	f := NewTestFeistel(t, "0123456789abcdef")
+	other := NewTestFeistel(t, "fedcba9876543210")
+
+	//ids from 1<<40 are permuted in 42 bit blocks, about half of bits must differ between permutations
+	//of adjacent ids and of one id with different keys
+	for id := uint64(1 << 40); id < 1<<40+1000; id++ {
+		if n := bits.OnesCount64(f.Permute(id) ^ f.Permute(id+1)); n < 6 || n > 36 {
+			t.Fatalf("Expected unrelated codes of %v and %v, %v bits differ", id, id+1, n)
+		}
+
+		if n := bits.OnesCount64(f.Permute(id) ^ other.Permute(id)); n < 6 || n > 36 {
+			t.Fatalf("Expected unrelated codes of %v with other key, %v bits differ", id, n)
+		}
+	}
+}
+
+func TestDomain(t *testing.T) {
+	f := NewTestFeistel(t, "0123456789abcdef")
+
+	//permutation of small ids is a bijection of ids with the same bit length rounded up to even number
+	seen := map[uint64]bool{}
+
+	for id := uint64(0); id < 1<<12; id++ {
+		number := f.Permute(id)
+
+		if size, min := domain(id); number < min || number >= 1<<size {
+			t.Fatalf("Expected %v to stay in domain of %v", number, id)
+		}
+
+		if seen[number] {
+			t.Fatalf("Expected bijection, %v is repeated", number)
+		}
+		seen[number] = true
+	}
+
+	tests := map[string]struct {
+		id     uint64
+		length int
+	}{
+		"Small id":   {1000, 2},
+		"Counter id": {1000000, 4},
+		"Random id":  {1<<63 + 12345, 11},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if code := f.Encode(tc.id); len(code) > tc.length {
+				t.Fatalf("Expected code not longer than %v, got %v", tc.length, code)
+			}
+		})
+	}
+}
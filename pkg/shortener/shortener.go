@@ -1,7 +1,116 @@
 package shortener
 
+import (
+	"errors"
+	"fmt"
+)
+
 //Shortener ...
 type Shortener interface {
 	Encode(uint64) string
 	Decode(string) (uint64, error)
 }
+
+var (
+	//ErrEmpty ...
+	ErrEmpty = errors.New("empty code")
+	//ErrInvalidCharacter - symbol is not in alphabet of codec
+	ErrInvalidCharacter = errors.New("invalid character")
+	//ErrOverflow - code is greater than max uint64
+	ErrOverflow = errors.New("code overflows uint64")
+	//ErrNonCanonical - code is decoded to id, but id is encoded to other code
+	ErrNonCanonical = errors.New("non-canonical code")
+	//ErrCheckCharacter - check character doesn't match code, e.g. code is mistyped
+	ErrCheckCharacter = errors.New("invalid check character")
+)
+
+//CodeError - code which can't be decoded, such code doesn't point to any link
+type CodeError struct {
+	Code string
+	Err  error
+	//Detail - e.g. invalid symbol
+	Detail string
+}
+
+func (e *CodeError) Error() string {
+	if e.Detail == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %s", e.Err, e.Detail)
+}
+
+//Unwrap ...
+func (e *CodeError) Unwrap() error {
+	return e.Err
+}
+
+//IsInvalidCode - err is returned by Decode for code which doesn't point to any link
+func IsInvalidCode(err error) bool {
+	var ce *CodeError
+	return errors.As(err, &ce)
+}
+
+//MultiDecoder - encodes with the first codec and decodes with all of them, so codes of previous codecs stay valid
+type MultiDecoder struct {
+	codecs []Shortener
+}
+
+//NewMultiDecoder - first codec is used for new codes
+func NewMultiDecoder(codecs ...Shortener) *MultiDecoder {
+	return &MultiDecoder{codecs: codecs}
+}
+
+//Encode ...
+func (m *MultiDecoder) Encode(number uint64) string {
+	return m.codecs[0].Encode(number)
+}
+
+//Decode - id of the first codec which decodes code, error of the first codec if no one does
+func (m *MultiDecoder) Decode(code string) (uint64, error) {
+	ids, err := m.DecodeAll(code)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
+//DecodeAll - distinct ids of code in order of codecs, the same code can be valid in several codecs.
+//Code with wrong check character is still decoded by the next codecs, so codes created before the check stay valid
+func (m *MultiDecoder) DecodeAll(code string) ([]uint64, error) {
+	var (
+		ids      []uint64
+		firstErr error
+	)
+
+	for _, c := range m.codecs {
+		id, err := c.Decode(code)
+
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, firstErr
+	}
+
+	return ids, nil
+}
+
+func contains(ids []uint64, id uint64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
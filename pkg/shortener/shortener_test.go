@@ -0,0 +1,79 @@
+package shortener_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/base62"
+	"github.com/VladimirStepanov/urlshortener/pkg/shortener/codec"
+)
+
+func TestMultiDecoder(t *testing.T) {
+	b58, _ := codec.New(codec.Base58)
+	m := shortener.NewMultiDecoder(b58, base62.New())
+
+	if code := m.Encode(333); code != "k6" {
+		t.Fatalf("Expected k6, got %v", code)
+	}
+
+	tests := map[string]struct {
+		code string
+		ids  []uint64
+		err  error
+	}{
+		"New code":        {"k6", []uint64{333, 3606}, nil},
+		"Existing code":   {"Ubrm0af", []uint64{284772472784}, nil},
+		"Invalid in both": {"Ub-m0af", nil, shortener.ErrInvalidCharacter},
+		"Look-alike code": {"l0", []uint64{3235}, nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ids, err := m.DecodeAll(tc.code)
+
+			if !errors.Is(err, tc.err) || !reflect.DeepEqual(ids, tc.ids) {
+				t.Fatalf("Expected %v %v, got %v %v", tc.ids, tc.err, ids, err)
+			}
+		})
+	}
+}
+
+func TestMultiDecoderCheckCharacter(t *testing.T) {
+	checked, _ := codec.New(codec.Base62, codec.CheckCharacter())
+	m := shortener.NewMultiDecoder(checked, base62.New())
+
+	if code := m.Encode(12345); code != "hndD" {
+		t.Fatalf("Expected hndD, got %v", code)
+	}
+
+	tests := map[string]struct {
+		code string
+		ids  []uint64
+		err  error
+	}{
+		"New code":        {"hndD", []uint64{12345, 6923857}, nil},
+		"Existing code":   {"Ubrm0af", []uint64{284772472784}, nil},
+		"Typo":            {"zndD", []uint64{6923875}, nil},
+		"Invalid in both": {"Ub-m0af", nil, shortener.ErrInvalidCharacter},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ids, err := m.DecodeAll(tc.code)
+
+			if !errors.Is(err, tc.err) || !reflect.DeepEqual(ids, tc.ids) {
+				t.Fatalf("Expected %v %v, got %v %v", tc.ids, tc.err, ids, err)
+			}
+		})
+	}
+}
+
+func TestIsInvalidCode(t *testing.T) {
+	_, err := base62.New().Decode("Ubrm0afa")
+
+	if !shortener.IsInvalidCode(err) || shortener.IsInvalidCode(errors.New("connection refused")) {
+		t.Fatalf("Expected only code errors to be invalid code")
+	}
+}
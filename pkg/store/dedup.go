@@ -0,0 +1,67 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+)
+
+//normalizer - normalization of URLs which are compared, tracking parameters are stripped before Save if it is enabled
+var normalizer = urlnorm.New()
+
+//NormalizeURL - canonical form of URL which is saved with item, URL which can't be parsed is returned as is
+func NormalizeURL(rawURL string) string {
+	normalized, err := normalizer.Normalize(rawURL)
+
+	if err != nil {
+		return rawURL
+	}
+
+	return normalized
+}
+
+//normalizeExpire - unix time of expire date, the same date can be written with and without leading zeros
+func normalizeExpire(expire string) string {
+	t, err := time.Parse("2.1.2006 15:4:5", expire)
+
+	if err != nil {
+		return expire
+	}
+
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+//Reusable - item can be returned instead of its duplicate: it is enabled, not expired and one-time item is not visited
+func Reusable(item *BaseItem, now time.Time) bool {
+	if item.Disabled || item.Once && item.Visits > 0 {
+		return false
+	}
+
+	expire, err := time.Parse("2.1.2006 15:4:5", item.Expire)
+
+	return err == nil && expire.After(now)
+}
+
+//Normalized - saved canonical form of URL, it is computed for items saved before it
+func (item *BaseItem) Normalized() string {
+	if item.NormalizedURL != "" {
+		return item.NormalizedURL
+	}
+
+	return NormalizeURL(item.URL)
+}
+
+//DedupHash - hash of normalized URL and options of item, items with the same hash are duplicates
+func DedupHash(item *BaseItem) string {
+	sum := sha256.New()
+
+	for _, s := range []string{item.Normalized(), normalizeExpire(item.Expire), strconv.FormatBool(item.Once)} {
+		sum.Write([]byte(s))
+		sum.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
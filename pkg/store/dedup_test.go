@@ -0,0 +1,62 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupHash(t *testing.T) {
+	base := &BaseItem{URL: "https://vk.com/feed?w=1", Expire: "10.1.2380 1:0:0"}
+
+	tests := map[string]struct {
+		item  *BaseItem
+		equal bool
+	}{
+		"Same item":       {&BaseItem{URL: "https://vk.com/feed?w=1", Expire: "10.1.2380 1:0:0", Visits: 10}, true},
+		"Upper case host": {&BaseItem{URL: "HTTPS://VK.com/feed?w=1", Expire: "10.1.2380 1:0:0"}, true},
+		"Default port":    {&BaseItem{URL: "https://vk.com:443/feed?w=1", Expire: "10.1.2380 1:0:0"}, true},
+		"Other port":      {&BaseItem{URL: "https://vk.com:8443/feed?w=1", Expire: "10.1.2380 1:0:0"}, false},
+		"Upper case path": {&BaseItem{URL: "https://vk.com/Feed?w=1", Expire: "10.1.2380 1:0:0"}, false},
+		"Leading zeros":   {&BaseItem{URL: "https://vk.com/feed?w=1", Expire: "10.01.2380 01:00:00"}, true},
+		"Other expire":    {&BaseItem{URL: "https://vk.com/feed?w=1", Expire: "11.1.2380 1:0:0"}, false},
+		"Once":            {&BaseItem{URL: "https://vk.com/feed?w=1", Expire: "10.1.2380 1:0:0", Once: true}, false},
+		"Without query":   {&BaseItem{URL: "https://vk.com/feed", Expire: "10.1.2380 1:0:0"}, false},
+		"Saved form":      {&BaseItem{URL: "https://vk.com/", NormalizedURL: "https://vk.com/feed?w=1", Expire: "10.1.2380 1:0:0"}, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if equal := DedupHash(tc.item) == DedupHash(base); equal != tc.equal {
+				t.Fatalf("Expected equal hashes %v, got %v", tc.equal, equal)
+			}
+		})
+	}
+
+	if DedupHash(&BaseItem{URL: "https://vk.com"}) != DedupHash(&BaseItem{URL: "https://vk.com/"}) {
+		t.Fatalf("Expected empty path to be equal to /")
+	}
+}
+
+func TestReusable(t *testing.T) {
+	now := time.Date(2021, 1, 10, 1, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		item     *BaseItem
+		reusable bool
+	}{
+		"Enabled":      {&BaseItem{Expire: "10.1.2380 1:0:0"}, true},
+		"Disabled":     {&BaseItem{Expire: "10.1.2380 1:0:0", Disabled: true}, false},
+		"Expired":      {&BaseItem{Expire: "10.1.2021 0:0:0"}, false},
+		"Once":         {&BaseItem{Expire: "10.1.2380 1:0:0", Once: true}, true},
+		"Visited once": {&BaseItem{Expire: "10.1.2380 1:0:0", Once: true, Visits: 1}, false},
+		"Bad expire":   {&BaseItem{Expire: "2380"}, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if reusable := Reusable(tc.item, now); reusable != tc.reusable {
+				t.Fatalf("Expected %v, got %v", tc.reusable, reusable)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/urlnorm"
+	"golang.org/x/net/publicsuffix"
+)
+
+//Domains - host of URL and its parent domains up to registrable domain (e.g. example.co.uk, not co.uk),
+//link is found by any of them. URL without host has no domains
+func Domains(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+
+	host, err := urlnorm.Domain(strings.TrimSuffix(u.Hostname(), "."))
+
+	if err != nil {
+		return nil
+	}
+
+	domains := []string{host}
+
+	//IP address, single label and public suffix have no registrable domain
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(host)
+
+	if net.ParseIP(host) != nil || err != nil {
+		return domains
+	}
+
+	for host != registrable {
+		host = host[strings.Index(host, ".")+1:]
+		domains = append(domains, host)
+	}
+
+	return domains
+}
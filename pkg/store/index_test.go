@@ -0,0 +1,33 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDomains(t *testing.T) {
+	tests := map[string]struct {
+		url     string
+		domains []string
+	}{
+		"Domain":         {"https://vk.com/feed", []string{"vk.com"}},
+		"Subdomain":      {"https://A.b.Evil.com:8080/", []string{"a.b.evil.com", "b.evil.com", "evil.com"}},
+		"IDN":            {"https://пример.рф/", []string{"xn--e1afmkfd.xn--p1ai"}},
+		"Trailing dot":   {"https://vk.com./", []string{"vk.com"}},
+		"Single label":   {"http://localhost/", []string{"localhost"}},
+		"IP":             {"http://10.0.0.1/", []string{"10.0.0.1"}},
+		"IPv6":           {"http://[::1]/", []string{"::1"}},
+		"Public suffix":  {"https://a.b.example.co.uk/", []string{"a.b.example.co.uk", "b.example.co.uk", "example.co.uk"}},
+		"Private suffix": {"https://user.github.io/", []string{"user.github.io"}},
+		"Suffix only":    {"https://co.uk/", []string{"co.uk"}},
+		"Without host":   {"mailto:user@vk.com", nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if domains := Domains(tc.url); !reflect.DeepEqual(domains, tc.domains) {
+				t.Fatalf("Expected %v, got %v", tc.domains, domains)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+//enableExpiredEvents - add expired events to keyspace notifications keeping other configured classes
+func enableExpiredEvents(conn redis.Conn) error {
+	values, err := redis.Strings(conn.Do("CONFIG", "GET", "notify-keyspace-events"))
+
+	if err != nil {
+		return err
+	}
+
+	flags := ""
+	if len(values) == 2 {
+		flags = values[1]
+	}
+
+	if strings.ContainsRune(flags, 'x') && (strings.ContainsRune(flags, 'E') || strings.ContainsRune(flags, 'A')) {
+		return nil
+	}
+
+	if !strings.ContainsRune(flags, 'E') {
+		flags += "E"
+	}
+	if !strings.ContainsRune(flags, 'x') && !strings.ContainsRune(flags, 'A') {
+		flags += "x"
+	}
+
+	_, err = conn.Do("CONFIG", "SET", "notify-keyspace-events", flags)
+
+	return err
+}
+
+//claimExpired - every instance receives notification, only the first one handles it
+func (rs *RedisStorage) claimExpired(id uint64) bool {
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	_, err := redis.String(conn.Do("SET", fmt.Sprintf("expired:%d", id), 1, "EX", 60, "NX"))
+
+	return err == nil
+}
+
+//WatchExpired - receive expired events of url:* keys. Notifications are enabled
+//if it is allowed by Redis, otherwise they must be enabled in Redis config
+func (rs *RedisStorage) WatchExpired(stop <-chan struct{}, fn func(id uint64)) error {
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	// managed Redis often forbids CONFIG, notifications can be enabled there by provider
+	enableExpiredEvents(conn)
+
+	psc := redis.PubSubConn{Conn: conn}
+
+	if err := psc.PSubscribe("__keyevent@*__:expired"); err != nil {
+		return err
+	}
+
+	// connection is closed only after unsubscribing goroutine is finished
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	wg.Add(1)
+	defer wg.Wait()
+	defer close(done)
+
+	go func() {
+		defer wg.Done()
+
+		select {
+		case <-stop:
+			psc.PUnsubscribe()
+		case <-done:
+		}
+	}()
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			key := string(v.Data)
+
+			if !strings.HasPrefix(key, "url:") {
+				continue
+			}
+
+			id, err := strconv.ParseUint(strings.TrimPrefix(key, "url:"), 10, 64)
+
+			if err == nil && rs.claimExpired(id) {
+				fn(id)
+			}
+		case redis.Subscription:
+			if v.Count == 0 {
+				return nil
+			}
+		case error:
+			return v
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/store"
+	"github.com/gomodule/redigo/redis"
+)
+
+//indexedKey - set when items created before domain index are added to it
+const indexedKey = "index:domains"
+
+//indexScript - add id to domain index, index expires with its last item. Without id only expire time is set,
+//index of expired items only is removed
+// KEYS[1] - domain index key, ARGV[1] - expire unix time of item, ARGV[2] - item id
+var indexScript = redis.NewScript(1, `
+if ARGV[2] then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+end
+local last = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+if last[2] then
+	redis.call('EXPIREAT', KEYS[1], last[2])
+end
+return 1
+`)
+
+//IndexDomains - add every item to domain index and set expire time of indexes created without it once,
+//later calls return 0. If it fails, it is repeated on next call
+func (rs *RedisStorage) IndexDomains() (int, error) {
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	if done, err := redis.Bool(conn.Do("EXISTS", indexedKey)); err != nil || done {
+		return 0, err
+	}
+
+	indexed := 0
+
+	err := rs.Walk(func(item *store.Item) error {
+		expire, err := time.Parse("2.1.2006 15:4:5", item.Expire)
+
+		if err != nil {
+			return nil
+		}
+
+		for _, d := range store.Domains(item.URL) {
+			if _, err = indexScript.Do(conn, domainKey(d), expire.Unix(), item.ID); err != nil {
+				return err
+			}
+		}
+
+		indexed++
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err = expireIndexes(conn); err != nil {
+		return 0, err
+	}
+
+	_, err = conn.Do("SET", indexedKey, 1)
+
+	return indexed, err
+}
+
+//expireIndexes - set expire time of every domain index
+func expireIndexes(conn redis.Conn) error {
+	cursor := 0
+
+	for {
+		values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", domainKey("*"), "COUNT", 100))
+
+		if err != nil {
+			return err
+		}
+
+		var keys []string
+
+		if _, err = redis.Scan(values, &cursor, &keys); err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if _, err = indexScript.Do(conn, key); err != nil {
+				return err
+			}
+		}
+
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
@@ -2,10 +2,12 @@ package redis
 
 import (
 	"fmt"
-	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/idgen"
 	"github.com/VladimirStepanov/urlshortener/pkg/store"
 	"github.com/gomodule/redigo/redis"
 )
@@ -13,19 +15,25 @@ import (
 //RedisStorage ...
 type RedisStorage struct {
 	pool *redis.Pool
+	ids  idgen.Allocator
 }
 
-//New - constructor for RedisStorage
-func New(c *config.Config) store.Storage {
-	s := &RedisStorage{
-		&redis.Pool{
-			MaxIdle:     10,
-			IdleTimeout: 240 * time.Second,
-			Dial: func() (redis.Conn, error) {
-				return redis.Dial("tcp", fmt.Sprintf("%s:%s", c.RedisHost, c.RedisPort))
-			},
+//NewPool - create Redis connection pool from config
+func NewPool(c *config.Config) *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", fmt.Sprintf("%s:%s", c.RedisHost, c.RedisPort))
 		},
 	}
+}
+
+//New - constructor for RedisStorage, ids of new links are taken from allocator
+func New(c *config.Config, ids idgen.Allocator) store.Storage {
+	s := &RedisStorage{
+		NewPool(c), ids,
+	}
 	return s
 }
 
@@ -57,7 +65,26 @@ func (rs *RedisStorage) Save(url string, expire time.Time, once bool) (uint64, e
 	defer conn.Close()
 
 	for {
-		id = rand.Uint64()
+		var (
+			err  error
+			free bool
+		)
+
+		// ids of key pool are checked when pool is filled
+		if c, ok := rs.ids.(idgen.Checked); ok {
+			id, free, err = c.NextChecked()
+		} else {
+			id, err = rs.ids.Next()
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if free {
+			break
+		}
+
 		exists, err := rs.isExists(id, conn)
 		if err != nil {
 			return 0, err
@@ -70,29 +97,86 @@ func (rs *RedisStorage) Save(url string, expire time.Time, once bool) (uint64, e
 
 	key := fmt.Sprintf("url:%d", id)
 
-	_, err := conn.Do(
+	//item and its domain index are written together, so item is never saved without index
+	conn.Send("MULTI")
+	conn.Send(
 		"HMSET", key,
 		"url", url,
+		"normalized_url", store.NormalizeURL(url),
 		"visits", 0,
 		"once", once,
 		"expire", expire.Format("2.1.2006 15:4:5"),
 	)
+	conn.Send("EXPIREAT", key, expire.Unix())
 
-	if err != nil {
-		return 0, err
+	for _, d := range store.Domains(url) {
+		indexScript.Send(conn, domainKey(d), expire.Unix(), id)
 	}
 
-	_, err = conn.Do(
-		"EXPIREAT", key, expire.Unix(),
-	)
-
-	if err != nil {
+	if _, err := conn.Do("EXEC"); err != nil {
 		return 0, err
 	}
 
 	return id, nil
 }
 
+//domainKey - sorted set of ids of links to domain and its subdomains, score is expire time
+func domainKey(domain string) string {
+	return fmt.Sprintf("domain:%s", domain)
+}
+
+func dedupKey(item *store.BaseItem) string {
+	return fmt.Sprintf("dedup:%s", store.DedupHash(item))
+}
+
+//SaveUnique - dedup:{hash} key points to item and expires with it. If two items are saved concurrently,
+//the one which doesn't get the key is removed. Key of item which is not reusable is replaced with new item
+func (rs *RedisStorage) SaveUnique(url string, expire time.Time, once bool) (uint64, bool, error) {
+	key := dedupKey(&store.BaseItem{URL: url, Expire: expire.Format("2.1.2006 15:4:5"), Once: once})
+
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	for {
+		existing, err := redis.Uint64(conn.Do("GET", key))
+
+		if err == nil {
+			item, err := rs.getItem(existing, conn)
+
+			if err == nil && store.Reusable(&item.BaseItem, time.Now()) {
+				return existing, false, nil
+			}
+
+			if err != nil && err != store.ErrItemNotFound {
+				return 0, false, err
+			}
+		} else if err != redis.ErrNil {
+			return 0, false, err
+		}
+
+		id, err := rs.Save(url, expire, once)
+
+		if err != nil {
+			return 0, false, err
+		}
+
+		//key of removed item is replaced, key of existing one is kept
+		replaced, err := redis.Bool(replaceScript.Do(conn, key, id, existing, expire.Unix()))
+
+		if err == nil && replaced {
+			return id, true, nil
+		}
+
+		if _, removeErr := rs.Remove(id); removeErr != nil {
+			return 0, false, removeErr
+		}
+
+		if err != nil {
+			return 0, false, err
+		}
+	}
+}
+
 func (rs *RedisStorage) getItem(id uint64, conn redis.Conn) (*store.Item, error) {
 	values, err := redis.Values(conn.Do("HGETALL", fmt.Sprintf("url:%d", id)))
 	if err != nil {
@@ -132,11 +216,15 @@ func (rs *RedisStorage) Remove(id uint64) (*store.Item, error) {
 		return nil, err
 	}
 
-	_, err = conn.Do(
-		"DEL", fmt.Sprintf("url:%d", id),
-	)
+	conn.Send("MULTI")
+	conn.Send("DEL", fmt.Sprintf("url:%d", id))
+	delIfEqualsScript.Send(conn, dedupKey(&res.BaseItem), id)
 
-	if err != nil {
+	for _, d := range store.Domains(res.URL) {
+		conn.Send("ZREM", domainKey(d), id)
+	}
+
+	if _, err = conn.Do("EXEC"); err != nil {
 		return nil, err
 	}
 
@@ -163,6 +251,159 @@ func (rs *RedisStorage) IncVisits(id uint64) error {
 	return err
 }
 
+// KEYS[1] - dedup key, ARGV[1] - new item id, ARGV[2] - id of removed item or 0, ARGV[3] - expire unix time
+var replaceScript = redis.NewScript(1, `
+local current = redis.call('GET', KEYS[1])
+if current and current ~= ARGV[2] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+redis.call('EXPIREAT', KEYS[1], ARGV[3])
+return 1
+`)
+
+// KEYS[1] - dedup key, ARGV[1] - item id
+var delIfEqualsScript = redis.NewScript(1, `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// KEYS[1] - item key, ARGV - field value pairs
+var setIfExistsScript = redis.NewScript(1, `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], unpack(ARGV))
+return 1
+`)
+
+//setFields - update fields of existing item without creating key without TTL
+func (rs *RedisStorage) setFields(id uint64, args ...interface{}) error {
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	updated, err := redis.Bool(setIfExistsScript.Do(conn, append([]interface{}{fmt.Sprintf("url:%d", id)}, args...)...))
+
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return store.ErrItemNotFound
+	}
+
+	return nil
+}
+
+//Disable ...
+func (rs *RedisStorage) Disable(id uint64, reason string) error {
+	return rs.setFields(id, "disabled", true, "disabled_reason", reason)
+}
+
+//Enable ...
+func (rs *RedisStorage) Enable(id uint64) error {
+	return rs.setFields(id, "disabled", false, "disabled_reason", "")
+}
+
+//SetTokenHash ...
+func (rs *RedisStorage) SetTokenHash(id uint64, hash string) error {
+	return rs.setFields(id, "token_hash", hash)
+}
+
+//SetOriginalURL ...
+func (rs *RedisStorage) SetOriginalURL(id uint64, url string) error {
+	return rs.setFields(id, "original_url", url)
+}
+
+//SetTrack ...
+func (rs *RedisStorage) SetTrack(id uint64) error {
+	return rs.setFields(id, "track", true)
+}
+
+//Walk - iterate over items with SCAN, items expired during iteration are skipped
+func (rs *RedisStorage) Walk(fn func(*store.Item) error) error {
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	cursor := 0
+
+	for {
+		values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", "url:*", "COUNT", 100))
+
+		if err != nil {
+			return err
+		}
+
+		var keys []string
+
+		_, err = redis.Scan(values, &cursor, &keys)
+
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			id, err := strconv.ParseUint(strings.TrimPrefix(key, "url:"), 10, 64)
+
+			if err != nil {
+				continue
+			}
+
+			item, err := rs.getItem(id, conn)
+
+			if err == store.ErrItemNotFound {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+//FindByDomain - expired ids are removed from index here, so index doesn't depend on keyspace notifications
+func (rs *RedisStorage) FindByDomain(domain string) ([]*store.Item, error) {
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	key := domainKey(domain)
+
+	if _, err := conn.Do("ZREMRANGEBYSCORE", key, "-inf", fmt.Sprintf("(%d", time.Now().Unix())); err != nil {
+		return nil, err
+	}
+
+	ids, err := redis.Uint64s(conn.Do("ZRANGE", key, 0, -1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*store.Item{}
+
+	for _, id := range ids {
+		item, err := rs.getItem(id, conn)
+
+		if err == store.ErrItemNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
 //Close - close pool
 func (rs *RedisStorage) Close() error {
 	return rs.pool.Close()
@@ -3,10 +3,12 @@ package redis
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/VladimirStepanov/urlshortener/pkg/config"
+	"github.com/VladimirStepanov/urlshortener/pkg/idgen"
 	"github.com/VladimirStepanov/urlshortener/pkg/store"
 	"github.com/gomodule/redigo/redis"
 )
@@ -57,6 +59,7 @@ func NewTestRedisStore(c *config.Config) *RedisStorage {
 				return redis.Dial("tcp", fmt.Sprintf("%s:%s", c.RedisHost, c.RedisPort))
 			},
 		},
+		idgen.Random{},
 	}
 
 	addKey(s, defaultItem)
@@ -276,3 +279,428 @@ func TestIncVisitsRedisStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestDisableRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	tests := map[string]struct {
+		id  uint64
+		err error
+	}{
+		"Success disable": {
+			id: defaultItem.ID,
+		},
+		"Error: item not found": {
+			id:  defaultItem.ID + 1,
+			err: store.ErrItemNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := rs.Disable(tc.id, "abuse")
+
+			if err != tc.err {
+				t.Fatalf("Expected errror: %v, but got: %v", tc.err, err)
+			}
+
+			if err == nil {
+				item, err := rs.Load(tc.id)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !item.Disabled || item.DisabledReason != "abuse" {
+					t.Fatalf("Expected disabled item, got %v", item)
+				}
+			}
+		})
+	}
+
+	exists, _ := rs.isExists(defaultItem.ID+1, rs.pool.Get())
+
+	if exists {
+		t.Fatalf("Disable must not create missing item")
+	}
+}
+
+func TestWalkRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	found := false
+
+	err := rs.Walk(func(item *store.Item) error {
+		if item.ID == defaultItem.ID {
+			found = reflect.DeepEqual(*item, *defaultItem)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found {
+		t.Fatalf("Expected %v to be visited", defaultItem)
+	}
+}
+
+func TestEnableRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	rs.Disable(defaultItem.ID, "abuse")
+
+	if err := rs.Enable(defaultItem.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := rs.Load(defaultItem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(*item, *defaultItem) {
+		t.Fatalf("Expected: %v, but got: %v", defaultItem, item)
+	}
+
+	if err := rs.Enable(defaultItem.ID + 1); err != store.ErrItemNotFound {
+		t.Fatalf("Expected errror: %v, but got: %v", store.ErrItemNotFound, err)
+	}
+}
+
+func TestClaimExpiredRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	conn := rs.pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", fmt.Sprintf("expired:%d", defaultItem.ID))
+
+	if !rs.claimExpired(defaultItem.ID) {
+		t.Fatalf("Expected first claim to succeed")
+	}
+
+	if rs.claimExpired(defaultItem.ID) {
+		t.Fatalf("Expected second claim to fail")
+	}
+}
+
+func TestSetTokenHashRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	if err := rs.SetTokenHash(defaultItem.ID, "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := rs.Load(defaultItem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.TokenHash != "hash" {
+		t.Fatalf("Expected token hash, got %v", item)
+	}
+
+	if err := rs.SetTokenHash(defaultItem.ID+1, "hash"); err != store.ErrItemNotFound {
+		t.Fatalf("Expected errror: %v, but got: %v", store.ErrItemNotFound, err)
+	}
+}
+
+func TestSetOriginalURLRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	if err := rs.SetOriginalURL(defaultItem.ID, "https://VK.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := rs.Load(defaultItem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.OriginalURL != "https://VK.com" || item.URL != defaultItem.URL {
+		t.Fatalf("Expected original URL, got %v", item)
+	}
+
+	if err := rs.SetOriginalURL(defaultItem.ID+1, "https://VK.com"); err != store.ErrItemNotFound {
+		t.Fatalf("Expected errror: %v, but got: %v", store.ErrItemNotFound, err)
+	}
+}
+
+func TestSaveNormalizedURLRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	id, err := rs.Save("HTTPS://VK.com:443/feed?b=2&a=1", time.Now().AddDate(1, 0, 0), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeKey(rs, id)
+
+	item, err := rs.Load(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.NormalizedURL != "https://vk.com/feed?a=1&b=2" || item.URL != "HTTPS://VK.com:443/feed?b=2&a=1" {
+		t.Fatalf("Expected normalized URL, got %v", item)
+	}
+}
+
+//testAllocator - allocator which returns ids from slice
+type testAllocator []uint64
+
+func (a *testAllocator) Next() (uint64, error) {
+	id := (*a)[0]
+	*a = (*a)[1:]
+	return id, nil
+}
+
+func TestSaveSkipsTakenIDRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	rs.ids = &testAllocator{defaultItem.ID, 7777}
+
+	id, err := rs.Save("https://vk.com", time.Now().AddDate(1, 0, 0), false)
+	defer removeKey(rs, id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 7777 {
+		t.Fatalf("Expected %v, got %v", 7777, id)
+	}
+}
+
+//checkedAllocator - ids of key pool which are known to be free
+type checkedAllocator struct {
+	testAllocator
+}
+
+func (a *checkedAllocator) NextChecked() (uint64, bool, error) {
+	id, err := a.Next()
+	return id, true, err
+}
+
+func TestSaveCheckedIDRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	rs.ids = &checkedAllocator{testAllocator{7778, 7779}}
+
+	id, err := rs.Save("https://vk.com", time.Now().AddDate(1, 0, 0), false)
+	defer removeKey(rs, id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 7778 {
+		t.Fatalf("Expected %v, got %v", 7778, id)
+	}
+}
+
+func TestSaveUniqueRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	expire := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	id, created, err := rs.SaveUnique("https://vk.com/dedup", expire, false)
+	defer removeKey(rs, id)
+
+	if err != nil || !created {
+		t.Fatalf("Expected new item, got %v %v", created, err)
+	}
+
+	conn := rs.pool.Get()
+	defer conn.Close()
+
+	key := dedupKey(&store.BaseItem{URL: "https://vk.com/dedup", Expire: expire.Format("2.1.2006 15:4:5")})
+
+	if ttl, _ := redis.Int64(conn.Do("TTL", key)); ttl <= 0 || ttl > 3600 {
+		t.Fatalf("Expected dedup key to expire with item, got ttl %v", ttl)
+	}
+
+	existing, created, err := rs.SaveUnique("HTTPS://VK.COM:443/dedup", expire, false)
+
+	if err != nil || created || existing != id {
+		t.Fatalf("Expected existing item %v, got %v %v %v", id, existing, created, err)
+	}
+
+	if _, err = rs.Remove(id); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := redis.Bool(conn.Do("EXISTS", key)); exists {
+		t.Fatalf("Expected dedup key to be removed with item")
+	}
+
+	//key of item removed without cleanup is replaced
+	conn.Do("SET", key, id)
+
+	newID, created, err := rs.SaveUnique("https://vk.com/dedup", expire, false)
+	defer removeKey(rs, newID)
+
+	if err != nil || !created || newID == id {
+		t.Fatalf("Expected new item, got %v %v %v", newID, created, err)
+	}
+
+	if current, _ := redis.Uint64(conn.Do("GET", key)); current != newID {
+		t.Fatalf("Expected dedup key to point to %v, got %v", newID, current)
+	}
+
+	//disabled item is not returned, key points to new item
+	if err = rs.Disable(newID, "abuse"); err != nil {
+		t.Fatal(err)
+	}
+
+	enabledID, created, err := rs.SaveUnique("https://vk.com/dedup", expire, false)
+	defer removeKey(rs, enabledID)
+
+	if err != nil || !created || enabledID == newID {
+		t.Fatalf("Expected new item, got %v %v %v", enabledID, created, err)
+	}
+
+	if current, _ := redis.Uint64(conn.Do("GET", key)); current != enabledID {
+		t.Fatalf("Expected dedup key to point to %v, got %v", enabledID, current)
+	}
+
+	conn.Do("DEL", key)
+}
+
+func TestFindByDomainRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	conn := rs.pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", domainKey("a.search.example"), domainKey("search.example"), domainKey("other.example"))
+
+	expire := time.Now().Add(time.Hour)
+	ids := map[string]uint64{}
+
+	for _, url := range []string{"https://a.search.example/x", "https://search.example/y", "https://other.example/"} {
+		id, err := rs.Save(url, expire, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer removeKey(rs, id)
+		ids[url] = id
+	}
+
+	//index expires with its last item
+	if ttl, err := redis.Int64(conn.Do("TTL", domainKey("search.example"))); err != nil || ttl <= 0 || ttl > 3600 {
+		t.Fatalf("Expected index to expire in an hour, got %v %v", ttl, err)
+	}
+
+	//expired id and id of item removed without cleanup are skipped
+	conn.Do("ZADD", domainKey("search.example"), time.Now().Add(-time.Hour).Unix(), 1, expire.Unix(), 2)
+
+	tests := map[string]struct {
+		domain string
+		urls   []string
+	}{
+		"Domain":    {"search.example", []string{"https://a.search.example/x", "https://search.example/y"}},
+		"Subdomain": {"a.search.example", []string{"https://a.search.example/x"}},
+		"Unknown":   {"unknown.example", []string{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			items, err := rs.FindByDomain(tc.domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			urls := []string{}
+			for _, item := range items {
+				urls = append(urls, item.URL)
+			}
+			sort.Strings(urls)
+
+			if !reflect.DeepEqual(urls, tc.urls) {
+				t.Fatalf("Expected %v, got %v", tc.urls, urls)
+			}
+		})
+	}
+
+	if score, _ := conn.Do("ZSCORE", domainKey("search.example"), 1); score != nil {
+		t.Fatalf("Expected expired id to be removed from index, got %v", score)
+	}
+
+	if _, err := rs.Remove(ids["https://a.search.example/x"]); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []string{"a.search.example", "search.example"} {
+		if score, _ := conn.Do("ZSCORE", domainKey(d), ids["https://a.search.example/x"]); score != nil {
+			t.Fatalf("Expected removed id to be removed from index of %v", d)
+		}
+	}
+
+	//public suffix is not indexed
+	id, err := rs.Save("https://shop.example.co.uk/", expire, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeKey(rs, id)
+	defer conn.Do("DEL", domainKey("shop.example.co.uk"), domainKey("example.co.uk"))
+
+	if score, _ := conn.Do("ZSCORE", domainKey("example.co.uk"), id); score == nil {
+		t.Fatalf("Expected id to be in index of registrable domain")
+	}
+
+	if exists, _ := redis.Bool(conn.Do("EXISTS", domainKey("co.uk"))); exists {
+		t.Fatalf("Expected public suffix not to be indexed")
+	}
+}
+
+func TestIndexDomainsRedisStorage(t *testing.T) {
+	rs := NewTestRedisStore(defaultConf)
+	defer CloseTestRedisStore(rs)
+
+	conn := rs.pool.Get()
+	defer conn.Close()
+	defer conn.Do("DEL", indexedKey, domainKey("old.example"))
+
+	//item created before domain index
+	old := &store.Item{ID: 1005, BaseItem: store.BaseItem{URL: "https://old.example/", Expire: "10.1.2380 1:0:0"}}
+	addKey(rs, old)
+	defer removeKey(rs, old.ID)
+	conn.Do("DEL", indexedKey)
+
+	//index of expired item created without expire time
+	conn.Do("ZADD", domainKey("stale.example"), 1000, 1006)
+
+	indexed, err := rs.IndexDomains()
+
+	if err != nil || indexed == 0 {
+		t.Fatalf("Expected indexed items, got %v %v", indexed, err)
+	}
+
+	if ttl, err := redis.Int64(conn.Do("TTL", domainKey("old.example"))); err != nil || ttl <= 0 {
+		t.Fatalf("Expected index with expire time, got %v %v", ttl, err)
+	}
+
+	if exists, err := redis.Bool(conn.Do("EXISTS", domainKey("stale.example"))); err != nil || exists {
+		t.Fatalf("Expected stale index to be removed, got %v %v", exists, err)
+	}
+
+	items, err := rs.FindByDomain("old.example")
+
+	if err != nil || len(items) != 1 || items[0].ID != old.ID {
+		t.Fatalf("Expected item %v, got %v %v", old.ID, items, err)
+	}
+
+	if indexed, err = rs.IndexDomains(); err != nil || indexed != 0 {
+		t.Fatalf("Expected index to be filled once, got %v %v", indexed, err)
+	}
+}
@@ -12,12 +12,28 @@ var (
 	ErrItemNotFound = fmt.Errorf("Item not found")
 )
 
+//PausedReason - disabled reason of item paused by its owner, only such item can be enabled by owner
+const PausedReason = "paused"
+
 //BaseItem ...
 type BaseItem struct {
 	URL    string `redis:"url" json:"url"`
 	Visits uint64 `redis:"visits" json:"visits"`
 	Expire string `redis:"expire" json:"expire"`
 	Once   bool   `redis:"once" json:"once"`
+	//OriginalURL - URL as it was submitted if it differs from normalized URL
+	OriginalURL string `redis:"original_url" json:"original_url,omitempty"`
+	//NormalizedURL - canonical form of URL, duplicates and links to URL are found by it. Empty for items saved before it
+	NormalizedURL string `redis:"normalized_url" json:"-"`
+
+	Disabled       bool   `redis:"disabled" json:"-"`
+	DisabledReason string `redis:"disabled_reason" json:"disabled_reason,omitempty"`
+
+	//TokenHash - SHA-256 of link management token
+	TokenHash string `redis:"token_hash" json:"-"`
+
+	//Track - conversions of item are tracked, redirects get click ids
+	Track bool `redis:"track" json:"track"`
 }
 
 //Item ...
@@ -29,8 +45,37 @@ type Item struct {
 //Storage ...
 type Storage interface {
 	Save(url string, expire time.Time, once bool) (uint64, error)
+	//SaveUnique - return existing item with the same normalized URL and options instead of creating new one.
+	//created is false for existing item
+	SaveUnique(url string, expire time.Time, once bool) (id uint64, created bool, err error)
 	Load(id uint64) (*Item, error)
 	Remove(id uint64) (*Item, error)
 	Close() error
 	IncVisits(id uint64) error
+	//Disable - stop redirects of item keeping its data
+	Disable(id uint64, reason string) error
+	//Enable - resume redirects of disabled item
+	Enable(id uint64) error
+	//SetTokenHash - set hash of management token
+	SetTokenHash(id uint64, hash string) error
+	//SetOriginalURL - keep URL as it was submitted before normalization
+	SetOriginalURL(id uint64, url string) error
+	//SetTrack - enable conversion tracking of item
+	SetTrack(id uint64) error
+	//Walk - call fn for every stored item
+	Walk(fn func(*Item) error) error
+	//FindByDomain - items with URL on normalized domain or its subdomains
+	FindByDomain(domain string) ([]*Item, error)
+}
+
+//DomainIndexer - storage which can miss items created before domain index in FindByDomain
+type DomainIndexer interface {
+	//IndexDomains - add such items to index, returns number of added items
+	IndexDomains() (int, error)
+}
+
+//ExpiryWatcher - storage which notifies about expired items
+type ExpiryWatcher interface {
+	//WatchExpired - call fn with id of every expired item until stop is closed or error happens
+	WatchExpired(stop <-chan struct{}, fn func(id uint64)) error
 }
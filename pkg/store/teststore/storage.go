@@ -45,11 +45,30 @@ func (rs *TestStorage) Save(url string, expire time.Time, once bool) (uint64, er
 		}
 	}
 
-	rs.items[id] = &store.Item{ID: id, BaseItem: store.BaseItem{URL: url, Visits: 0, Expire: expire.Format("2.1.2006 15:4:5"), Once: once}}
+	rs.items[id] = &store.Item{ID: id, BaseItem: store.BaseItem{URL: url, NormalizedURL: store.NormalizeURL(url), Visits: 0, Expire: expire.Format("2.1.2006 15:4:5"), Once: once}}
 
 	return id, nil
 }
 
+//SaveUnique - look for reusable duplicate among all items
+func (rs *TestStorage) SaveUnique(url string, expire time.Time, once bool) (uint64, bool, error) {
+	hash := store.DedupHash(&store.BaseItem{URL: url, Expire: expire.Format("2.1.2006 15:4:5"), Once: once})
+
+	for id, item := range rs.items {
+		if store.DedupHash(&item.BaseItem) != hash {
+			continue
+		}
+
+		if _, err := rs.getItem(id); err == nil && store.Reusable(&item.BaseItem, time.Now()) {
+			return id, false, nil
+		}
+	}
+
+	id, err := rs.Save(url, expire, once)
+
+	return id, err == nil, err
+}
+
 func (rs *TestStorage) getItem(id uint64) (*store.Item, error) {
 	item, ok := rs.items[id]
 
@@ -104,6 +123,107 @@ func (rs *TestStorage) IncVisits(id uint64) error {
 	return nil
 }
 
+//Disable ...
+func (rs *TestStorage) Disable(id uint64, reason string) error {
+	item, err := rs.getItem(id)
+
+	if err != nil {
+		return err
+	}
+
+	item.Disabled = true
+	item.DisabledReason = reason
+
+	return nil
+}
+
+//Enable ...
+func (rs *TestStorage) Enable(id uint64) error {
+	item, err := rs.getItem(id)
+
+	if err != nil {
+		return err
+	}
+
+	item.Disabled = false
+	item.DisabledReason = ""
+
+	return nil
+}
+
+//SetTokenHash ...
+func (rs *TestStorage) SetTokenHash(id uint64, hash string) error {
+	item, err := rs.getItem(id)
+
+	if err != nil {
+		return err
+	}
+
+	item.TokenHash = hash
+
+	return nil
+}
+
+//SetOriginalURL ...
+func (rs *TestStorage) SetOriginalURL(id uint64, url string) error {
+	item, err := rs.getItem(id)
+
+	if err != nil {
+		return err
+	}
+
+	item.OriginalURL = url
+
+	return nil
+}
+
+//SetTrack ...
+func (rs *TestStorage) SetTrack(id uint64) error {
+	item, err := rs.getItem(id)
+
+	if err != nil {
+		return err
+	}
+
+	item.Track = true
+
+	return nil
+}
+
+//FindByDomain ...
+func (rs *TestStorage) FindByDomain(domain string) ([]*store.Item, error) {
+	items := []*store.Item{}
+
+	err := rs.Walk(func(item *store.Item) error {
+		for _, d := range store.Domains(item.URL) {
+			if d == domain {
+				items = append(items, item)
+				break
+			}
+		}
+		return nil
+	})
+
+	return items, err
+}
+
+//Walk ...
+func (rs *TestStorage) Walk(fn func(*store.Item) error) error {
+	for id := range rs.items {
+		item, err := rs.getItem(id)
+
+		if err != nil {
+			continue
+		}
+
+		if err = fn(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 //Close - close pool
 func (rs *TestStorage) Close() error {
 	return nil
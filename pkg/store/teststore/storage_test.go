@@ -180,3 +180,173 @@ func TestIncVisitsTestStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestDisableTestStorage(t *testing.T) {
+	tests := map[string]struct {
+		id  uint64
+		err error
+	}{
+		"Success disable": {
+			id: defaultItem.ID,
+		},
+		"Error: item not found": {
+			id:  defaultItem.ID + 1,
+			err: store.ErrItemNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rs := New(map[uint64]*store.Item{1: {ID: 1, BaseItem: defaultItem.BaseItem}})
+
+			err := rs.Disable(tc.id, "abuse")
+
+			if err != tc.err {
+				t.Fatalf("Expected errror: %v, but got: %v", tc.err, err)
+			}
+
+			if err == nil {
+				item, _ := rs.Load(tc.id)
+				if !item.Disabled || item.DisabledReason != "abuse" {
+					t.Fatalf("Expected disabled item, got %v", item)
+				}
+			}
+		})
+	}
+}
+
+func TestWalkTestStorage(t *testing.T) {
+	rs := New(map[uint64]*store.Item{
+		1: {ID: 1, BaseItem: defaultItem.BaseItem},
+		2: {ID: 2, BaseItem: store.BaseItem{URL: "https://vk.com", Expire: "10.1.1994 1:0:0"}},
+		3: {ID: 3, BaseItem: defaultItem.BaseItem},
+	})
+
+	visited := map[uint64]bool{}
+
+	err := rs.Walk(func(item *store.Item) error {
+		visited[item.ID] = true
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[uint64]bool{1: true, 3: true}
+
+	if !reflect.DeepEqual(visited, expected) {
+		t.Fatalf("Expected: %v, but got: %v", expected, visited)
+	}
+}
+
+func TestEnableTestStorage(t *testing.T) {
+	rs := New(map[uint64]*store.Item{1: {ID: 1, BaseItem: defaultItem.BaseItem}})
+
+	rs.Disable(1, "abuse")
+
+	if err := rs.Enable(1); err != nil {
+		t.Fatal(err)
+	}
+
+	item, _ := rs.Load(1)
+
+	if item.Disabled || item.DisabledReason != "" {
+		t.Fatalf("Expected enabled item, got %v", item)
+	}
+
+	if err := rs.Enable(2); err != store.ErrItemNotFound {
+		t.Fatalf("Expected errror: %v, but got: %v", store.ErrItemNotFound, err)
+	}
+}
+
+func TestSetTokenHashTestStorage(t *testing.T) {
+	rs := New(map[uint64]*store.Item{1: {ID: 1, BaseItem: defaultItem.BaseItem}})
+
+	if err := rs.SetTokenHash(1, "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	if item, _ := rs.Load(1); item.TokenHash != "hash" {
+		t.Fatalf("Expected token hash, got %v", item)
+	}
+
+	if err := rs.SetTokenHash(2, "hash"); err != store.ErrItemNotFound {
+		t.Fatalf("Expected errror: %v, but got: %v", store.ErrItemNotFound, err)
+	}
+}
+
+func TestSetOriginalURLTestStorage(t *testing.T) {
+	rs := New(map[uint64]*store.Item{1: {ID: 1, BaseItem: defaultItem.BaseItem}})
+
+	if err := rs.SetOriginalURL(1, "https://VK.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if item, _ := rs.Load(1); item.OriginalURL != "https://VK.com" {
+		t.Fatalf("Expected original URL, got %v", item)
+	}
+
+	if err := rs.SetOriginalURL(2, "https://VK.com"); err != store.ErrItemNotFound {
+		t.Fatalf("Expected errror: %v, but got: %v", store.ErrItemNotFound, err)
+	}
+}
+
+func TestSaveUniqueTestStorage(t *testing.T) {
+	rs := &TestStorage{
+		items: map[uint64]*store.Item{
+			1: {ID: 1, BaseItem: store.BaseItem{URL: "https://vk.com/a", Expire: "10.1.2380 1:0:0"}},
+			2: {ID: 2, BaseItem: store.BaseItem{URL: "https://vk.com/paused", Expire: "10.1.2380 1:0:0", Disabled: true, DisabledReason: "paused"}},
+			3: {ID: 3, BaseItem: store.BaseItem{URL: "https://vk.com/once", Expire: "10.1.2380 1:0:0", Once: true, Visits: 1}},
+		},
+	}
+	expire := time.Date(2380, 1, 10, 1, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		url     string
+		once    bool
+		created bool
+	}{
+		"Duplicate":          {"https://VK.com/a", false, false},
+		"Other options":      {"https://vk.com/a", true, true},
+		"Disabled duplicate": {"https://vk.com/paused", false, true},
+		"Visited once":       {"https://vk.com/once", true, true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, created, err := rs.SaveUnique(tc.url, expire, tc.once)
+
+			if err != nil || created != tc.created {
+				t.Fatalf("Expected created %v, got %v %v %v", tc.created, id, created, err)
+			}
+
+			if !created && id != 1 {
+				t.Fatalf("Expected existing item 1, got %v", id)
+			}
+		})
+	}
+}
+
+func TestFindByDomainTestStorage(t *testing.T) {
+	rs := GetTestStore()
+
+	tests := map[string]struct {
+		domain string
+		count  int
+	}{
+		"Domain":       {"vk.com", 1},
+		"Other domain": {"ok.ru", 0},
+		"TLD":          {"com", 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			items, err := rs.FindByDomain(tc.domain)
+
+			if err != nil || len(items) != tc.count {
+				t.Fatalf("Expected %v items, got %v %v", tc.count, len(items), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/gomodule/redigo/redis"
+)
+
+//Message - stream entry with event
+type Message struct {
+	ID    string
+	Event events.Event
+}
+
+//Handler - process message, entry is not acknowledged if error is returned
+type Handler func(m *Message) error
+
+//Consumer - reads stream as member of consumer group. Entry is acknowledged after handler
+//succeeds. If handler fails, Run returns error and the entry is delivered again on next Run,
+//because consumer starts from its pending entries
+type Consumer struct {
+	pool  *redis.Pool
+	key   string
+	group string
+	name  string
+	//Start - position of new group, "0" is the whole stream and "$" is only new entries
+	Start string
+	Block time.Duration
+	Count int
+}
+
+//NewConsumer ...
+func NewConsumer(pool *redis.Pool, key, group, name string) *Consumer {
+	return &Consumer{pool: pool, key: key, group: group, name: name, Start: "0", Block: 5 * time.Second, Count: 100}
+}
+
+//createGroup - create group if it doesn't exist
+func (c *Consumer) createGroup(conn redis.Conn) error {
+	_, err := conn.Do("XGROUP", "CREATE", c.key, c.group, c.Start, "MKSTREAM")
+
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+
+	return err
+}
+
+//Run - read and handle entries until stop is closed or handler fails
+func (c *Consumer) Run(stop <-chan struct{}, handle Handler) error {
+	conn := c.pool.Get()
+	defer conn.Close()
+
+	if err := c.createGroup(conn); err != nil {
+		return err
+	}
+
+	// pending entries of this consumer first, then new ones
+	pos := "0"
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		args := redis.Args{"GROUP", c.group, c.name, "COUNT", c.Count}
+		if pos == ">" {
+			args = args.Add("BLOCK", c.Block.Milliseconds())
+		}
+
+		messages, err := read(conn.Do("XREADGROUP", args.Add("STREAMS", c.key, pos)...))
+
+		if err != nil {
+			return err
+		}
+
+		if pos != ">" && len(messages) == 0 {
+			pos = ">"
+			continue
+		}
+
+		for _, m := range messages {
+			if m.err == nil {
+				if err := handle(&m.Message); err != nil {
+					return fmt.Errorf("handle %s: %v", m.ID, err)
+				}
+			}
+			// malformed entries are acknowledged too, they can't be handled anyway
+
+			if _, err := conn.Do("XACK", c.key, c.group, m.ID); err != nil {
+				return err
+			}
+
+			if pos != ">" {
+				pos = m.ID
+			}
+		}
+	}
+}
+
+type entry struct {
+	Message
+	err error
+}
+
+//read - parse reply of XREAD and XREADGROUP for one stream
+func read(reply interface{}, err error) ([]entry, error) {
+	streams, err := redis.Values(reply, err)
+
+	if err == redis.ErrNil {
+		// timeout of BLOCK
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := []entry{}
+
+	for _, s := range streams {
+		pair, err := redis.Values(s, nil)
+
+		if err != nil || len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected stream reply: %v", s)
+		}
+
+		items, err := redis.Values(pair[1], nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			parts, err := redis.Values(item, nil)
+
+			if err != nil || len(parts) != 2 {
+				return nil, fmt.Errorf("unexpected entry reply: %v", item)
+			}
+
+			id, err := redis.String(parts[0], nil)
+
+			if err != nil {
+				return nil, err
+			}
+
+			// fields of deleted pending entry are nil
+			fields, _ := redis.StringMap(parts[1], nil)
+
+			e := entry{Message: Message{ID: id}}
+			e.Event, e.err = Decode(fields)
+
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/gomodule/redigo/redis"
+)
+
+const testStream = "test:events"
+
+func publishTestEvents(t *testing.T, p *Publisher, links ...string) {
+	for i, link := range links {
+		if err := p.Publish(events.New(events.LinkVisited, uint64(i), link, "https://vk.com")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func removeStream(p *Publisher, keys ...string) {
+	conn := p.pool.Get()
+	defer conn.Close()
+
+	conn.Do("DEL", redis.Args{testStream}.AddFlat(keys)...)
+}
+
+//collect - handler which fails on link fail and stops after link last
+func collect(seen *[]string, fail, last string, stop chan struct{}) Handler {
+	return func(m *Message) error {
+		if m.Event.Link == fail {
+			return errors.New("fail")
+		}
+
+		*seen = append(*seen, m.Event.Link)
+
+		if m.Event.Link == last {
+			close(stop)
+		}
+		return nil
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	p := NewPublisher(NewTestPool(), testStream, 100)
+	defer removeStream(p)
+
+	publishTestEvents(t, p, "a", "b", "c")
+
+	c := NewConsumer(p.pool, testStream, "analytics", "worker-1")
+	c.Block = 100 * time.Millisecond
+
+	seen := []string{}
+
+	if err := c.Run(nil, collect(&seen, "b", "", nil)); err == nil {
+		t.Fatalf("Expected handler error")
+	}
+
+	// b is pending and it is delivered again
+	stop := make(chan struct{})
+
+	if err := c.Run(stop, collect(&seen, "", "c", stop)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != 3 || seen[0] != "a" || seen[1] != "b" || seen[2] != "c" {
+		t.Fatalf("Expected [a b c], got %v", seen)
+	}
+}
+
+func TestReader(t *testing.T) {
+	p := NewPublisher(NewTestPool(), testStream, 100)
+	cp := NewKeyCheckpoint(p.pool, "test:events:position")
+	defer removeStream(p, "test:events:position")
+
+	publishTestEvents(t, p, "a", "b", "c")
+
+	r := NewReader(p.pool, testStream, cp)
+	r.Block = 100 * time.Millisecond
+
+	seen := []string{}
+
+	if err := r.Run(nil, collect(&seen, "b", "", nil)); err == nil {
+		t.Fatalf("Expected handler error")
+	}
+
+	stop := make(chan struct{})
+
+	if err := r.Run(stop, collect(&seen, "", "c", stop)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != 3 || seen[0] != "a" || seen[1] != "b" || seen[2] != "c" {
+		t.Fatalf("Expected [a b c], got %v", seen)
+	}
+}
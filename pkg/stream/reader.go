@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+//Checkpoint - saved position of Reader
+type Checkpoint interface {
+	//Load - saved position or "0" if there is no saved position
+	Load() (string, error)
+	Save(id string) error
+}
+
+//KeyCheckpoint - position saved in Redis key
+type KeyCheckpoint struct {
+	pool *redis.Pool
+	key  string
+}
+
+//NewKeyCheckpoint ...
+func NewKeyCheckpoint(pool *redis.Pool, key string) *KeyCheckpoint {
+	return &KeyCheckpoint{pool: pool, key: key}
+}
+
+//Load ...
+func (c *KeyCheckpoint) Load() (string, error) {
+	conn := c.pool.Get()
+	defer conn.Close()
+
+	pos, err := redis.String(conn.Do("GET", c.key))
+
+	if err == redis.ErrNil {
+		return "0", nil
+	}
+
+	return pos, err
+}
+
+//Save ...
+func (c *KeyCheckpoint) Save(id string) error {
+	conn := c.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SET", c.key, id)
+
+	return err
+}
+
+//Reader - reads stream without consumer group, position is saved after every handled entry.
+//If handler fails, Run returns error and the entry is read again on next Run
+type Reader struct {
+	pool       *redis.Pool
+	key        string
+	checkpoint Checkpoint
+	Block      time.Duration
+	Count      int
+}
+
+//NewReader ...
+func NewReader(pool *redis.Pool, key string, checkpoint Checkpoint) *Reader {
+	return &Reader{pool: pool, key: key, checkpoint: checkpoint, Block: 5 * time.Second, Count: 100}
+}
+
+//Run - read and handle entries until stop is closed or handler fails
+func (r *Reader) Run(stop <-chan struct{}, handle Handler) error {
+	pos, err := r.checkpoint.Load()
+
+	if err != nil {
+		return err
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		messages, err := read(conn.Do("XREAD", "COUNT", r.Count, "BLOCK", r.Block.Milliseconds(), "STREAMS", r.key, pos))
+
+		if err != nil {
+			return err
+		}
+
+		for _, m := range messages {
+			if m.err == nil {
+				if err := handle(&m.Message); err != nil {
+					return fmt.Errorf("handle %s: %v", m.ID, err)
+				}
+			}
+
+			if err := r.checkpoint.Save(m.ID); err != nil {
+				return err
+			}
+
+			pos = m.ID
+		}
+	}
+}
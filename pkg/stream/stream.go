@@ -0,0 +1,110 @@
+//Package stream publishes link events to capped Redis Stream and reads them back.
+//
+//Every entry has flat string fields, empty fields are omitted:
+//
+//	v          - schema version, "1"
+//	id         - event id, unique hex string
+//	type       - link.created, link.deleted, link.visited, link.expired, link.disabled, link.enabled
+//	time       - event time, RFC3339 with nanoseconds in UTC
+//	link       - encoded URL
+//	link_id    - numeric id of link
+//	url        - destination URL
+//	referrer   - Referer header of visit
+//	user_agent - User-Agent header of visit
+//	reason     - disabled reason of link.disabled
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/gomodule/redigo/redis"
+)
+
+//Version - schema version of entries
+const Version = "1"
+
+//ErrVersion ...
+var ErrVersion = errors.New("unsupported entry version")
+
+//Encode - fields of stream entry
+func Encode(e events.Event) redis.Args {
+	args := redis.Args{"v", Version, "id", e.ID, "type", e.Type, "time", e.Time.UTC().Format(time.RFC3339Nano),
+		"link", e.Link, "link_id", strconv.FormatUint(e.LinkID, 10)}
+
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"url", e.URL},
+		{"referrer", e.Referrer},
+		{"user_agent", e.UserAgent},
+		{"reason", e.Reason},
+	} {
+		if f.value != "" {
+			args = args.Add(f.name, f.value)
+		}
+	}
+
+	return args
+}
+
+//Decode - event from fields of stream entry
+func Decode(fields map[string]string) (events.Event, error) {
+	if fields["v"] != Version {
+		return events.Event{}, ErrVersion
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, fields["time"])
+
+	if err != nil {
+		return events.Event{}, err
+	}
+
+	linkID, err := strconv.ParseUint(fields["link_id"], 10, 64)
+
+	if err != nil {
+		return events.Event{}, err
+	}
+
+	return events.Event{
+		ID:        fields["id"],
+		Type:      fields["type"],
+		Time:      t,
+		LinkID:    linkID,
+		Link:      fields["link"],
+		URL:       fields["url"],
+		Referrer:  fields["referrer"],
+		UserAgent: fields["user_agent"],
+		Reason:    fields["reason"],
+	}, nil
+}
+
+//Publisher - appends events to stream, stream is trimmed to about maxLen entries
+type Publisher struct {
+	pool   *redis.Pool
+	key    string
+	maxLen int
+}
+
+//NewPublisher ...
+func NewPublisher(pool *redis.Pool, key string, maxLen int) *Publisher {
+	return &Publisher{pool: pool, key: key, maxLen: maxLen}
+}
+
+//Publish ...
+func (p *Publisher) Publish(e events.Event) error {
+	conn := p.pool.Get()
+	defer conn.Close()
+
+	args := redis.Args{p.key}
+
+	if p.maxLen > 0 {
+		args = args.Add("MAXLEN", "~", p.maxLen)
+	}
+
+	_, err := conn.Do("XADD", args.Add("*").Add(Encode(e)...)...)
+
+	return err
+}
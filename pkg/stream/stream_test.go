@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestPool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+}
+
+func fields(args redis.Args) map[string]string {
+	res := map[string]string{}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		res[args[i].(string)] = args[i+1].(string)
+	}
+
+	return res
+}
+
+func TestEncodeDecode(t *testing.T) {
+	e := events.New(events.LinkVisited, 4000, "OTv0FdGU8Ng", "https://vk.com")
+	e.Referrer = "https://google.com/"
+
+	f := fields(Encode(e))
+
+	if _, ok := f["user_agent"]; ok {
+		t.Fatalf("Expected empty fields to be omitted, got %v", f)
+	}
+
+	res, err := Decode(f)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, e) {
+		t.Fatalf("Expected %v, got %v", e, res)
+	}
+
+	f["v"] = "2"
+
+	if _, err = Decode(f); err != ErrVersion {
+		t.Fatalf("Expected %v, got %v", ErrVersion, err)
+	}
+}
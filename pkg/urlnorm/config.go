@@ -0,0 +1,14 @@
+package urlnorm
+
+import (
+	"github.com/VladimirStepanov/urlshortener/pkg/config"
+)
+
+//NewFromConfig - TrackingParams are stripped only if StripTrackingParams is set
+func NewFromConfig(c *config.Config) *Normalizer {
+	if !c.StripTrackingParams {
+		return New()
+	}
+
+	return New(c.TrackingParams...)
+}
@@ -0,0 +1,146 @@
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+//defaultPorts - ports which are removed from host
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+//Normalizer - canonical form of URL, so the same destination written differently is stored once
+type Normalizer struct {
+	strip []string
+}
+
+//New - query parameters matched by strip are removed, name ended with * is prefix
+func New(strip ...string) *Normalizer {
+	n := &Normalizer{}
+
+	for _, p := range strip {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			n.strip = append(n.strip, p)
+		}
+	}
+
+	return n
+}
+
+//Normalize - lower case scheme and host, punycode host, no default port, "/" for empty path of http(s) URL
+//and sorted query without stripped parameters
+func (n *Normalizer) Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	if u.Host != "" {
+		host, err := Domain(u.Hostname())
+
+		if err != nil {
+			return "", err
+		}
+
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		if port := u.Port(); port != "" && defaultPorts[u.Scheme] != port {
+			host += ":" + port
+		}
+
+		u.Host = host
+	}
+
+	if u.Path == "" && defaultPorts[u.Scheme] != "" {
+		u.Path = "/"
+	}
+
+	u.RawQuery = n.query(u.RawQuery)
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+//Domain - host mapped as for DNS lookup: lower case, full-width symbols replaced by ASCII ones and
+//internationalized labels converted to punycode. Host with invalid labels is an error, IP address is kept as is
+func Domain(host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return strings.ToLower(host), nil
+	}
+
+	return idna.Lookup.ToASCII(host)
+}
+
+//Strip - URL without stripped query parameters, other parts of URL and order of parameters are kept
+func (n *Normalizer) Strip(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := strings.Join(n.params(u.RawQuery), "&")
+
+	if query == u.RawQuery {
+		return rawURL, nil
+	}
+
+	u.RawQuery = query
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+//params - parameters of query which are not stripped
+func (n *Normalizer) params(raw string) []string {
+	var params []string
+
+	for _, p := range strings.Split(raw, "&") {
+		if p != "" && !n.stripped(paramName(p)) {
+			params = append(params, p)
+		}
+	}
+
+	return params
+}
+
+//query - parameters sorted by name, order of values of the same parameter is kept
+func (n *Normalizer) query(raw string) string {
+	params := n.params(raw)
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return paramName(params[i]) < paramName(params[j])
+	})
+
+	return strings.Join(params, "&")
+}
+
+func paramName(p string) string {
+	name := strings.SplitN(p, "=", 2)[0]
+
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		return unescaped
+	}
+
+	return name
+}
+
+func (n *Normalizer) stripped(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, p := range n.strip {
+		if strings.HasSuffix(p, "*") && strings.HasPrefix(name, strings.TrimSuffix(p, "*")) || name == p {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package urlnorm
+
+import "testing"
+
+func TestDomain(t *testing.T) {
+	tests := map[string]struct {
+		host     string
+		expected string
+		isError  bool
+	}{
+		"ASCII":         {"vk.com", "vk.com", false},
+		"Upper case":    {"VK.com", "vk.com", false},
+		"Latin":         {"münchen.de", "xn--mnchen-3ya.de", false},
+		"Cyrillic":      {"Пример.рф", "xn--e1afmkfd.xn--p1ai", false},
+		"Repeated rune": {"bücher.example", "xn--bcher-kva.example", false},
+		"Chinese":       {"例子.测试", "xn--fsqu00a.xn--0zwm56d", false},
+		"Full-width":    {"ＥＸＡＭＰＬＥ.com", "example.com", false},
+		"IPv6":          {"::1", "::1", false},
+		"Underscore":    {"my_host.example", "", true},
+		"Leading dash":  {"-vk.com", "", true},
+		"Bad punycode":  {"xn--zz.com", "", true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := Domain(tc.host)
+
+			if tc.isError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.isError, err)
+			}
+
+			if !tc.isError && res != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	n := New("utm_*", "fbclid", "GCLID")
+
+	tests := map[string]struct {
+		url      string
+		expected string
+	}{
+		"Already normal":  {"https://vk.com/feed?a=1", "https://vk.com/feed?a=1"},
+		"Upper case":      {"HTTPS://VK.com/Feed", "https://vk.com/Feed"},
+		"Default port":    {"http://vk.com:80/", "http://vk.com/"},
+		"Other port":      {"https://vk.com:8443/", "https://vk.com:8443/"},
+		"Empty path":      {"https://vk.com", "https://vk.com/"},
+		"IDN":             {"https://Пример.РФ/", "https://xn--e1afmkfd.xn--p1ai/"},
+		"Full-width host": {"https://ＥＸＡＭＰＬＥ.com/", "https://example.com/"},
+		"IPv6":            {"http://[::1]:80/", "http://[::1]/"},
+		"Sorted query":    {"https://vk.com/?b=2&a=1&b=1", "https://vk.com/?a=1&b=2&b=1"},
+		"Escaped name":    {"https://vk.com/?%62=1&a=2", "https://vk.com/?a=2&%62=1"},
+		"Empty query":     {"https://vk.com/?", "https://vk.com/"},
+		"Tracking params": {"https://vk.com/?utm_source=x&id=1&fbclid=y&gclid=z&UTM_medium=m", "https://vk.com/?id=1"},
+		"Only tracking":   {"https://vk.com/?utm_source=x", "https://vk.com/"},
+		"Similar name":    {"https://vk.com/?utm=1&gclids=2", "https://vk.com/?gclids=2&utm=1"},
+		"Fragment":        {"https://vk.com/#Top", "https://vk.com/#Top"},
+		"Without host":    {"MAILTO:user@vk.com", "mailto:user@vk.com"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := n.Normalize(tc.url)
+
+			if err != nil || res != tc.expected {
+				t.Fatalf("Expected %v, got %v %v", tc.expected, res, err)
+			}
+		})
+	}
+
+	if res, _ := New().Normalize("https://vk.com/?utm_source=x"); res != "https://vk.com/?utm_source=x" {
+		t.Fatalf("Expected tracking params to be kept, got %v", res)
+	}
+
+	if _, err := n.Normalize("https://vk.com:port/"); err == nil {
+		t.Fatalf("Expected error for invalid URL, but got nil")
+	}
+
+	if _, err := n.Normalize("https://-vk.com/"); err == nil {
+		t.Fatalf("Expected error for invalid host, but got nil")
+	}
+}
+
+func TestStrip(t *testing.T) {
+	n := New("utm_*", "fbclid")
+
+	tests := map[string]struct {
+		url      string
+		expected string
+	}{
+		"Without params":  {"HTTPS://ＥＸＡＭＰＬＥ.com:443/Feed?b=2&a=1", "HTTPS://ＥＸＡＭＰＬＥ.com:443/Feed?b=2&a=1"},
+		"Tracking params": {"https://vk.com/feed?b=2&utm_source=x&a=1&fbclid=y#top", "https://vk.com/feed?b=2&a=1#top"},
+		"Only tracking":   {"https://vk.com/?utm_source=x", "https://vk.com/"},
+		"Without query":   {"https://vk.com", "https://vk.com"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := n.Strip(tc.url)
+
+			if err != nil || res != tc.expected {
+				t.Fatalf("Expected %v, got %v %v", tc.expected, res, err)
+			}
+		})
+	}
+
+	if _, err := n.Strip("https://vk.com:port/"); err == nil {
+		t.Fatalf("Expected error for invalid URL, but got nil")
+	}
+}
@@ -0,0 +1,33 @@
+package useragent
+
+import (
+	"strings"
+)
+
+//crawlers - link previewers of messengers and social networks and search engine crawlers.
+//Generic http libraries and in-app browsers are not here, they are used by people to follow links
+var crawlers = []string{
+	// previewers
+	"slackbot", "slack-imgproxy", "skypeuripreview", "microsoftpreview",
+	"facebookexternalhit", "facebot", "twitterbot", "whatsapp", "telegrambot", "discordbot",
+	"linkedinbot", "pinterestbot", "vkshare", "redditbot", "embedly",
+	"iframely", "outbrain", "quora link preview", "google-pagerenderer", "mattermost", "rocket.chat",
+	// crawlers
+	"googlebot", "adsbot-google", "mediapartners-google", "bingbot", "yandex", "baiduspider",
+	"duckduckbot", "applebot", "petalbot", "ahrefsbot", "semrushbot", "mj12bot",
+	// generic
+	"crawler", "spider", "bot/", "bot;", "+http",
+}
+
+//IsCrawler - User-Agent of link previewer or crawler, they must not consume links
+func IsCrawler(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+
+	for _, c := range crawlers {
+		if strings.Contains(ua, c) {
+			return true
+		}
+	}
+
+	return false
+}
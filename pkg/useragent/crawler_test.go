@@ -0,0 +1,31 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestIsCrawler(t *testing.T) {
+	tests := map[string]struct {
+		ua      string
+		crawler bool
+	}{
+		"Slack":       {"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		"Teams":       {"Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) SkypeUriPreview Preview/0.5", true},
+		"iMessage":    {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_1) AppleWebKit/601.2.4 (KHTML, like Gecko) Version/9.0.1 Safari/601.2.4 facebookexternalhit/1.1 Facebot Twitterbot/1.0", true},
+		"Telegram":    {"TelegramBot (like TwitterBot)", true},
+		"WhatsApp":    {"WhatsApp/2.20.200.22 A", true},
+		"Googlebot":   {"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", true},
+		"Chrome":      {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.75 Safari/537.36", false},
+		"curl":        {"curl/7.68.0", false},
+		"Go client":   {"Go-http-client/1.1", false},
+		"Empty agent": {"", false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := IsCrawler(tc.ua); res != tc.crawler {
+				t.Fatalf("Expected %v, got %v", tc.crawler, res)
+			}
+		})
+	}
+}
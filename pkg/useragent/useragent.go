@@ -0,0 +1,112 @@
+package useragent
+
+import (
+	"strings"
+)
+
+//Device classes
+const (
+	DeviceBot     = "bot"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	Unknown       = "unknown"
+)
+
+//Agent - parsed User-Agent header
+type Agent struct {
+	Browser string
+	OS      string
+	Device  string
+}
+
+type rule struct {
+	token string
+	name  string
+}
+
+//browsers - order matters, many browsers mention Chrome and Safari in their User-Agent
+var browsers = []rule{
+	{"edg/", "Edge"},
+	{"edge/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"yabrowser/", "Yandex Browser"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"ucbrowser/", "UC Browser"},
+	{"vivaldi/", "Vivaldi"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chromium/", "Chromium"},
+	{"chrome/", "Chrome"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+}
+
+var systems = []rule{
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"ipod", "iOS"},
+	{"cros", "Chrome OS"},
+	{"android", "Android"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+var bots = []string{
+	"bot", "crawler", "spider", "slurp", "facebookexternalhit", "preview", "curl/", "wget/",
+	"python-requests", "go-http-client", "headless",
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		if strings.Contains(ua, r.token) {
+			return r.name
+		}
+	}
+
+	return Unknown
+}
+
+//IsBot - User-Agent of crawler, previewer or http library
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+
+	for _, b := range bots {
+		if strings.Contains(ua, b) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Parse ...
+func Parse(userAgent string) Agent {
+	if strings.TrimSpace(userAgent) == "" {
+		return Agent{Unknown, Unknown, Unknown}
+	}
+
+	ua := strings.ToLower(userAgent)
+	a := Agent{Browser: match(ua, browsers), OS: match(ua, systems)}
+
+	switch {
+	case IsBot(ua):
+		a.Device = DeviceBot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		a.Device = DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		a.Device = DeviceMobile
+	default:
+		a.Device = DeviceDesktop
+	}
+
+	return a
+}
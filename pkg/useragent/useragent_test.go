@@ -0,0 +1,61 @@
+package useragent
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		ua    string
+		agent Agent
+	}{
+		"Chrome on Windows": {
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.75 Safari/537.36",
+			Agent{"Chrome", "Windows", DeviceDesktop},
+		},
+		"Edge on Windows": {
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.75 Safari/537.36 Edg/86.0.622.38",
+			Agent{"Edge", "Windows", DeviceDesktop},
+		},
+		"Safari on iPhone": {
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 14_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Mobile/15E148 Safari/604.1",
+			Agent{"Safari", "iOS", DeviceMobile},
+		},
+		"Chrome on Android phone": {
+			"Mozilla/5.0 (Linux; Android 10; SM-G975F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.75 Mobile Safari/537.36",
+			Agent{"Chrome", "Android", DeviceMobile},
+		},
+		"Android tablet": {
+			"Mozilla/5.0 (Linux; Android 9; SM-T820) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.4240.75 Safari/537.36",
+			Agent{"Chrome", "Android", DeviceTablet},
+		},
+		"Firefox on Linux": {
+			"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:81.0) Gecko/20100101 Firefox/81.0",
+			Agent{"Firefox", "Linux", DeviceDesktop},
+		},
+		"Safari on macOS": {
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Safari/605.1.15",
+			Agent{"Safari", "macOS", DeviceDesktop},
+		},
+		"Googlebot": {
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Agent{Unknown, Unknown, DeviceBot},
+		},
+		"curl": {
+			"curl/7.68.0",
+			Agent{"curl", Unknown, DeviceBot},
+		},
+		"Empty": {
+			"",
+			Agent{Unknown, Unknown, Unknown},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := Parse(tc.ua); res != tc.agent {
+				t.Fatalf("Expected %v, got %v", tc.agent, res)
+			}
+		})
+	}
+}
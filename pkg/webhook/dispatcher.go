@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/policy"
+)
+
+//batchSize - max number of deliveries sent at once
+const batchSize = 20
+
+//Dispatcher - publishes events to matching subscriptions and delivers them with retries
+type Dispatcher struct {
+	store       Store
+	client      *http.Client
+	maxAttempts int
+	now         func() time.Time
+}
+
+//NewDispatcher - deliveries to private addresses are refused unless allowPrivate is set
+func NewDispatcher(store Store, timeout time.Duration, maxAttempts int, allowPrivate bool) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		client:      policy.NewClient(timeout, allowPrivate),
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+	}
+}
+
+//Publish - schedule delivery of event to every subscription of its type
+func (d *Dispatcher) Publish(e events.Event) error {
+	subs, err := d.store.Subscriptions()
+
+	if err != nil {
+		return err
+	}
+
+	for _, s := range subs {
+		if !s.Matches(e.Type) {
+			continue
+		}
+
+		err = d.store.Schedule(&Delivery{ID: events.NewID(), Subscription: s.ID, Event: e}, d.now())
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Deliver - send due deliveries. Failed ones are scheduled again with backoff
+//and dropped after max attempts, error of the last dropped delivery is returned
+func (d *Dispatcher) Deliver() (int, error) {
+	now := d.now()
+
+	deliveries, err := d.store.Claim(now, d.client.Timeout+30*time.Second, batchSize)
+
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	subs, err := d.store.Subscriptions()
+
+	if err != nil {
+		return 0, err
+	}
+
+	byID := make(map[string]Subscription, len(subs))
+	for _, s := range subs {
+		byID[s.ID] = s
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sent    int
+		lastErr error
+	)
+
+	for i := range deliveries {
+		wg.Add(1)
+
+		go func(del *Delivery) {
+			defer wg.Done()
+
+			ok, err := d.deliver(byID, del)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if ok {
+				sent++
+			}
+			if err != nil {
+				lastErr = err
+			}
+		}(&deliveries[i])
+	}
+
+	wg.Wait()
+
+	return sent, lastErr
+}
+
+//deliver - send delivery and update queue, returns error if delivery is dropped
+func (d *Dispatcher) deliver(subs map[string]Subscription, del *Delivery) (bool, error) {
+	sub, ok := subs[del.Subscription]
+
+	if !ok {
+		// subscription is removed
+		return false, d.store.Done(del.ID)
+	}
+
+	err := d.send(&sub, del)
+
+	if err == nil {
+		return true, d.store.Done(del.ID)
+	}
+
+	del.Attempt++
+	del.LastError = err.Error()
+
+	if del.Attempt >= d.maxAttempts {
+		if err := d.store.Done(del.ID); err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("delivery %s of %s to %s is dropped after %d attempts: %v", del.ID, del.Event.Type, sub.URL, del.Attempt, err)
+	}
+
+	return false, d.store.Schedule(del, d.now().Add(Backoff(del.Attempt)))
+}
+
+//send - POST signed event to subscription URL
+func (d *Dispatcher) send(sub *Subscription, del *Delivery) error {
+	body, err := json.Marshal(del.Event)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", sub.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	ts := d.now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, del.Event.Type)
+	req.Header.Set(HeaderDelivery, del.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, ts, body))
+
+	resp, err := d.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+//Run - deliver due events every interval until stop is closed
+func (d *Dispatcher) Run(interval time.Duration, stop <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for {
+				sent, err := d.Deliver()
+
+				if err != nil && onError != nil {
+					onError(err)
+				}
+
+				// queue can have more due deliveries
+				if sent < batchSize {
+					break
+				}
+			}
+		}
+	}
+}
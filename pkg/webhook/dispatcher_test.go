@@ -0,0 +1,114 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook/memory"
+)
+
+func TestDispatcher(t *testing.T) {
+	received := make(chan events.Event, 10)
+	fail := true
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+
+		if !webhook.Verify("0123456789abcdef", ts, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		e := events.Event{}
+		json.Unmarshal(body, &e)
+		received <- e
+	}))
+	defer srv.Close()
+
+	store := memory.New()
+	store.Subscribe(webhook.NewSubscription(srv.URL, []string{events.LinkVisited}, "0123456789abcdef"))
+
+	d := webhook.NewDispatcher(store, time.Second, 3, true)
+
+	d.Publish(events.New(events.LinkCreated, 1, "b", "https://vk.com"))
+	d.Publish(events.New(events.LinkVisited, 1, "b", "https://vk.com"))
+
+	// receiver is down, delivery is scheduled again
+	if sent, err := d.Deliver(); sent != 0 || err != nil {
+		t.Fatalf("Expected failed attempt without error, got %v %v", sent, err)
+	}
+
+	// retry is not due yet
+	if sent, _ := d.Deliver(); sent != 0 {
+		t.Fatalf("Expected no due deliveries, got %v", sent)
+	}
+
+	pending, _ := store.Claim(time.Now().Add(webhook.BaseDelay), time.Minute, 10)
+
+	if len(pending) != 1 || pending[0].Attempt != 1 || pending[0].Event.Type != events.LinkVisited {
+		t.Fatalf("Expected one retry of link.visited, got %v", pending)
+	}
+
+	fail = false
+	store.Schedule(&pending[0], time.Now())
+
+	if sent, err := d.Deliver(); sent != 1 || err != nil {
+		t.Fatalf("Expected one sent delivery, got %v %v", sent, err)
+	}
+
+	if e := <-received; e.Type != events.LinkVisited || e.Link != "b" || e.URL != "https://vk.com" {
+		t.Fatalf("Unexpected event %v", e)
+	}
+}
+
+func TestDispatcherDrop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	store := memory.New()
+	store.Subscribe(webhook.NewSubscription(srv.URL, []string{events.LinkDeleted}, ""))
+
+	d := webhook.NewDispatcher(store, time.Second, 1, true)
+	d.Publish(events.New(events.LinkDeleted, 1, "b", "https://vk.com"))
+
+	if _, err := d.Deliver(); err == nil {
+		t.Fatalf("Expected error of dropped delivery")
+	}
+
+	if pending, _ := store.Claim(time.Now().Add(time.Hour), time.Minute, 10); len(pending) != 0 {
+		t.Fatalf("Expected empty queue, got %v", pending)
+	}
+}
+
+func TestDispatcherRefusesPrivate(t *testing.T) {
+	received := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer srv.Close()
+
+	store := memory.New()
+	store.Subscribe(webhook.NewSubscription(srv.URL, []string{events.LinkDeleted}, ""))
+
+	d := webhook.NewDispatcher(store, time.Second, 1, false)
+	d.Publish(events.New(events.LinkDeleted, 1, "b", "https://vk.com"))
+
+	if _, err := d.Deliver(); err == nil || received {
+		t.Fatalf("Expected delivery to loopback address to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+)
+
+type queued struct {
+	delivery webhook.Delivery
+	at       time.Time
+}
+
+//Store - in-memory subscriptions and deliveries for single instance and tests, queue is lost on restart
+type Store struct {
+	mu    sync.Mutex
+	subs  map[string]webhook.Subscription
+	queue map[string]*queued
+}
+
+//New ...
+func New() *Store {
+	return &Store{subs: map[string]webhook.Subscription{}, queue: map[string]*queued{}}
+}
+
+//Subscribe ...
+func (s *Store) Subscribe(sub *webhook.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subs[sub.ID] = *sub
+
+	return nil
+}
+
+//Subscriptions - oldest first
+func (s *Store) Subscriptions() ([]webhook.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]webhook.Subscription, 0, len(s.subs))
+
+	for _, sub := range s.subs {
+		res = append(res, sub)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+//Unsubscribe ...
+func (s *Store) Unsubscribe(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[id]; !ok {
+		return webhook.ErrNotFound
+	}
+
+	delete(s.subs, id)
+
+	return nil
+}
+
+//Schedule ...
+func (s *Store) Schedule(d *webhook.Delivery, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queue[d.ID] = &queued{*d, at}
+
+	return nil
+}
+
+//Claim ...
+func (s *Store) Claim(now time.Time, lease time.Duration, limit int) ([]webhook.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := []*queued{}
+
+	for _, q := range s.queue {
+		if !q.at.After(now) {
+			due = append(due, q)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].at.Before(due[j].at)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	res := make([]webhook.Delivery, 0, len(due))
+
+	for _, q := range due {
+		q.at = now.Add(lease)
+		res = append(res, q.delivery)
+	}
+
+	return res, nil
+}
+
+//Done ...
+func (s *Store) Done(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.queue, id)
+
+	return nil
+}
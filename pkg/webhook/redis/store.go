@@ -0,0 +1,174 @@
+package redis
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	subscriptionsKey = "webhook:subscriptions"
+	queueKey         = "webhook:queue"
+	deliveriesKey    = "webhook:deliveries"
+)
+
+// KEYS[1] - queue, ARGV[1] - now in ms, ARGV[2] - lease end in ms, ARGV[3] - limit
+var claimScript = redis.NewScript(1, `
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(ids) do
+	redis.call('ZADD', KEYS[1], ARGV[2], id)
+end
+return ids
+`)
+
+//Store - subscriptions in hash webhook:subscriptions, deliveries in hash webhook:deliveries
+//and their due time in sorted set webhook:queue, so queue survives restarts
+type Store struct {
+	pool *redis.Pool
+}
+
+//New ...
+func New(pool *redis.Pool) *Store {
+	return &Store{pool: pool}
+}
+
+func ms(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+//Subscribe ...
+func (s *Store) Subscribe(sub *webhook.Subscription) error {
+	data, err := json.Marshal(sub)
+
+	if err != nil {
+		return err
+	}
+
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("HSET", subscriptionsKey, sub.ID, data)
+
+	return err
+}
+
+//Subscriptions - oldest first
+func (s *Store) Subscriptions() ([]webhook.Subscription, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	values, err := redis.StringMap(conn.Do("HGETALL", subscriptionsKey))
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]webhook.Subscription, 0, len(values))
+
+	for _, data := range values {
+		sub := webhook.Subscription{}
+
+		if err := json.Unmarshal([]byte(data), &sub); err != nil {
+			return nil, err
+		}
+
+		res = append(res, sub)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+//Unsubscribe ...
+func (s *Store) Unsubscribe(id string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	removed, err := redis.Int(conn.Do("HDEL", subscriptionsKey, id))
+
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return webhook.ErrNotFound
+	}
+
+	return nil
+}
+
+//Schedule ...
+func (s *Store) Schedule(d *webhook.Delivery, at time.Time) error {
+	data, err := json.Marshal(d)
+
+	if err != nil {
+		return err
+	}
+
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("HSET", deliveriesKey, d.ID, data)
+	conn.Send("ZADD", queueKey, ms(at), d.ID)
+	_, err = conn.Do("EXEC")
+
+	return err
+}
+
+//Claim ...
+func (s *Store) Claim(now time.Time, lease time.Duration, limit int) ([]webhook.Delivery, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	ids, err := redis.Strings(claimScript.Do(conn, queueKey, ms(now), ms(now.Add(lease)), limit))
+
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	values, err := redis.ByteSlices(conn.Do("HMGET", redis.Args{deliveriesKey}.AddFlat(ids)...))
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]webhook.Delivery, 0, len(values))
+
+	for i, data := range values {
+		if data == nil {
+			// delivery is already done
+			conn.Do("ZREM", queueKey, ids[i])
+			continue
+		}
+
+		d := webhook.Delivery{}
+
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+
+		res = append(res, d)
+	}
+
+	return res, nil
+}
+
+//Done ...
+func (s *Store) Done(id string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("ZREM", queueKey, id)
+	conn.Send("HDEL", deliveriesKey, id)
+	_, err := conn.Do("EXEC")
+
+	return err
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/webhook"
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewTestStore() *Store {
+	return New(&redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	})
+}
+
+func removeWebhooks(s *Store) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	conn.Do("DEL", subscriptionsKey, queueKey, deliveriesKey)
+}
+
+func TestStoreRedis(t *testing.T) {
+	s := NewTestStore()
+	defer removeWebhooks(s)
+
+	sub := webhook.NewSubscription("http://127.0.0.1/hook", []string{"link.created"}, "")
+
+	s.Subscribe(sub)
+
+	subs, _ := s.Subscriptions()
+	if len(subs) != 1 || subs[0].ID != sub.ID {
+		t.Fatalf("Expected %v, got %v", sub, subs)
+	}
+
+	now := time.Now()
+
+	s.Schedule(&webhook.Delivery{ID: "1", Subscription: sub.ID}, now.Add(-time.Second))
+	s.Schedule(&webhook.Delivery{ID: "2", Subscription: sub.ID}, now.Add(time.Minute))
+
+	claimed, _ := s.Claim(now, time.Minute, 10)
+	if len(claimed) != 1 || claimed[0].ID != "1" {
+		t.Fatalf("Expected delivery 1, got %v", claimed)
+	}
+
+	// claimed delivery is hidden until lease ends
+	if claimed, _ = s.Claim(now, time.Minute, 10); len(claimed) != 0 {
+		t.Fatalf("Expected no deliveries, got %v", claimed)
+	}
+
+	s.Done("1")
+
+	if claimed, _ = s.Claim(now.Add(2*time.Minute), time.Minute, 10); len(claimed) != 1 || claimed[0].ID != "2" {
+		t.Fatalf("Expected delivery 2, got %v", claimed)
+	}
+
+	if err := s.Unsubscribe(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Unsubscribe(sub.ID); err != webhook.ErrNotFound {
+		t.Fatalf("Expected %v, got %v", webhook.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/VladimirStepanov/urlshortener/pkg/events"
+)
+
+const (
+	//BaseDelay - delay before the first retry, it is doubled after every failed attempt
+	BaseDelay = 10 * time.Second
+	//MaxDelay - max delay between attempts
+	MaxDelay = time.Hour
+)
+
+//Headers of delivery request
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+//ErrNotFound ...
+var ErrNotFound = errors.New("subscription not found")
+
+//Subscription - URL which receives events of listed types
+type Subscription struct {
+	ID      string    `json:"id"`
+	URL     string    `json:"url"`
+	Events  []string  `json:"events"`
+	Secret  string    `json:"secret,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+//NewSubscription - subscription with random ID, random secret is generated if it is empty
+func NewSubscription(url string, types []string, secret string) *Subscription {
+	if secret == "" {
+		secret = events.NewID()
+	}
+
+	return &Subscription{ID: events.NewID(), URL: url, Events: types, Secret: secret, Created: time.Now().UTC()}
+}
+
+//Matches - subscription receives events of type
+func (s *Subscription) Matches(typ string) bool {
+	for _, t := range s.Events {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Delivery - event which must be sent to subscription
+type Delivery struct {
+	ID           string       `json:"id"`
+	Subscription string       `json:"subscription"`
+	Event        events.Event `json:"event"`
+	Attempt      int          `json:"attempt"`
+	LastError    string       `json:"last_error,omitempty"`
+}
+
+//Store - subscriptions and persistent queue of deliveries
+type Store interface {
+	Subscribe(s *Subscription) error
+	Subscriptions() ([]Subscription, error)
+	Unsubscribe(id string) error
+	//Schedule - add delivery or update existing one, it is due at time at
+	Schedule(d *Delivery, at time.Time) error
+	//Claim - due deliveries, they are hidden from other workers for lease or until they are scheduled again
+	Claim(now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	//Done - remove delivery from queue
+	Done(id string) error
+}
+
+//Backoff - delay after failed attempt
+func Backoff(attempt int) time.Duration {
+	delay := BaseDelay
+
+	for i := 1; i < attempt && delay < MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > MaxDelay {
+		delay = MaxDelay
+	}
+
+	return delay
+}
+
+//Sign - hex encoded HMAC-SHA256 of timestamp and body joined with dot
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//Verify - check signature of received delivery, receivers should also reject old timestamps
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := map[string]struct {
+		attempt int
+		delay   time.Duration
+	}{
+		"First retry":  {1, 10 * time.Second},
+		"Second retry": {2, 20 * time.Second},
+		"Fifth retry":  {5, 160 * time.Second},
+		"Max delay":    {20, MaxDelay},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := Backoff(tc.attempt); res != tc.delay {
+				t.Fatalf("Expected %v, got %v", tc.delay, res)
+			}
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"link.visited"}`)
+	signature := Sign("secret", 1601831880, body)
+
+	tests := map[string]struct {
+		secret    string
+		timestamp int64
+		body      []byte
+		exp       bool
+	}{
+		"Valid":         {"secret", 1601831880, body, true},
+		"Wrong secret":  {"other", 1601831880, body, false},
+		"Wrong time":    {"secret", 1601831881, body, false},
+		"Modified body": {"secret", 1601831880, []byte(`{"type":"link.deleted"}`), false},
+		"Empty body":    {"secret", 1601831880, nil, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if res := Verify(tc.secret, tc.timestamp, tc.body, signature); res != tc.exp {
+				t.Fatalf("Expected %v, got %v", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestNewSubscription(t *testing.T) {
+	s := NewSubscription("http://127.0.0.1/hook", []string{"link.created"}, "")
+
+	if s.ID == "" || len(s.Secret) != 32 {
+		t.Fatalf("Expected generated id and secret, got %v", s)
+	}
+
+	if !s.Matches("link.created") || s.Matches("link.visited") {
+		t.Fatalf("Expected subscription only to link.created, got %v", s.Events)
+	}
+}